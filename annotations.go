@@ -0,0 +1,458 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"image"
+	"io/fs"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Annotation kinds.
+const (
+	annotationPin    = "pin"
+	annotationNote   = "note"
+	annotationRect   = "rect"
+	annotationCircle = "circle"
+)
+
+// annotationTools are the buttons of the annotation bar. The erase tool has
+// no annotation kind.
+var annotationTools = []struct {
+	label, kind string
+}{
+	{"Pin", annotationPin},
+	{"Note", annotationNote},
+	{"Rect", annotationRect},
+	{"Circle", annotationCircle},
+	{"Erase", ""},
+}
+
+// annotation is a user mark anchored in world tile coordinates.
+type annotation struct {
+	Kind string `json:"kind"`
+	X    int    `json:"x"`
+	Y    int    `json:"y"`
+	// X2 and Y2 are the opposite corner of a rectangle or a point on the
+	// edge of a circle centered at X, Y.
+	X2   int    `json:"x2,omitempty"`
+	Y2   int    `json:"y2,omitempty"`
+	Text string `json:"text,omitempty"`
+}
+
+// annotationStore holds the annotations of every seed, keyed by
+// annotationKey.
+type annotationStore map[string][]annotation
+
+func annotationKey(coord, asteroid string) string {
+	return strings.ToUpper(strings.TrimSpace(coord)) + "/" + asteroid
+}
+
+// loadAnnotationStore reads the saved annotations. A missing file yields an
+// empty store.
+func loadAnnotationStore() (annotationStore, error) {
+	data, err := readUserData(AnnotationsFile)
+	return decodeAnnotationStore(data, err)
+}
+
+// decodeAnnotationStore decodes the saved annotations read with readErr.
+// Unreadable or invalid data yields an empty store and the error so the file
+// is not overwritten.
+func decodeAnnotationStore(data []byte, readErr error) (annotationStore, error) {
+	s := annotationStore{}
+	if errors.Is(readErr, fs.ErrNotExist) {
+		return s, nil
+	}
+	if readErr != nil {
+		return s, readErr
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return annotationStore{}, err
+	}
+	return s, nil
+}
+
+func (s annotationStore) save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeUserData(AnnotationsFile, data)
+}
+
+// annotationAt returns the index of the topmost annotation in list within
+// tol tiles of the world position x, y, or -1.
+func annotationAt(list []annotation, x, y, tol float64) int {
+	for i := len(list) - 1; i >= 0; i-- {
+		a := list[i]
+		ax, ay := float64(a.X), float64(a.Y)
+		switch a.Kind {
+		case annotationRect:
+			x0, x1 := math.Min(ax, float64(a.X2)), math.Max(ax, float64(a.X2))
+			y0, y1 := math.Min(ay, float64(a.Y2)), math.Max(ay, float64(a.Y2))
+			if x >= x0-tol && x <= x1+tol && y >= y0-tol && y <= y1+tol {
+				return i
+			}
+		case annotationCircle:
+			r := math.Hypot(float64(a.X2)-ax, float64(a.Y2)-ay)
+			if math.Hypot(x-ax, y-ay) <= r+tol {
+				return i
+			}
+		default:
+			if math.Hypot(x-ax, y-ay) <= tol {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// annotationStore returns the saved annotations, loading them on first use.
+func (g *Game) annotationStore() annotationStore {
+	if g.annotations == nil {
+		g.annotations, g.annotLoadErr = loadAnnotationStore()
+		if g.annotLoadErr != nil {
+			g.annotErr = "Saved annotations could not be loaded: " + g.annotLoadErr.Error()
+		}
+	}
+	return g.annotations
+}
+
+// currentAnnotations returns the annotations of the shown asteroid.
+func (g *Game) currentAnnotations() []annotation {
+	return g.annotationStore()[annotationKey(g.coord, g.asteroidID)]
+}
+
+// setAnnotations replaces the annotations stored under key and saves them.
+func (g *Game) setAnnotations(key string, list []annotation) {
+	store := g.annotationStore()
+	if len(list) == 0 {
+		delete(store, key)
+	} else {
+		store[key] = list
+	}
+	g.needsRedraw = true
+	if g.annotLoadErr != nil {
+		// Saving would replace the annotations that failed to load.
+		g.annotErr = "Annotations not saved, the saved file could not be loaded: " + g.annotLoadErr.Error()
+		return
+	}
+	g.annotErr = ""
+	if err := store.save(); err != nil {
+		g.annotErr = "Annotations not saved: " + err.Error()
+	}
+}
+
+func (g *Game) openAnnotate() {
+	g.closeMenus()
+	g.annotating = true
+	g.showAnnotations = true
+	g.needsRedraw = true
+}
+
+func (g *Game) closeAnnotate() {
+	g.finishAnnotationText()
+	g.annotating = false
+	g.annotPress = nil
+	g.annotDrag = nil
+	g.needsRedraw = true
+}
+
+// addAnnotation stores a on the shown asteroid and starts editing its text.
+func (g *Game) addAnnotation(a annotation) {
+	key := annotationKey(g.coord, g.asteroidID)
+	list := append(g.currentAnnotations(), a)
+	g.setAnnotations(key, list)
+	g.annotEditing = true
+	g.annotEditKey = key
+	g.annotEdit = len(list) - 1
+	g.annotText = ""
+}
+
+// finishAnnotationText saves the typed text of the annotation being edited.
+// Notes left without text are removed.
+func (g *Game) finishAnnotationText() {
+	if !g.annotEditing {
+		return
+	}
+	g.annotEditing = false
+	list := append([]annotation(nil), g.annotationStore()[g.annotEditKey]...)
+	if g.annotEdit >= len(list) {
+		return
+	}
+	text := strings.TrimSpace(g.annotText)
+	if text == "" && list[g.annotEdit].Kind == annotationNote {
+		list = append(list[:g.annotEdit], list[g.annotEdit+1:]...)
+	} else {
+		list[g.annotEdit].Text = text
+	}
+	g.setAnnotations(g.annotEditKey, list)
+}
+
+// eraseAnnotation removes the annotation at the world position x, y.
+func (g *Game) eraseAnnotation(x, y float64) {
+	list := g.currentAnnotations()
+	tol := float64(uiScaled(FindMarkerRadius)) / (2 * g.zoom)
+	if i := annotationAt(list, x, y, tol); i >= 0 {
+		list = append(append([]annotation(nil), list[:i]...), list[i+1:]...)
+		g.setAnnotations(annotationKey(g.coord, g.asteroidID), list)
+	}
+}
+
+// screenToWorld converts a screen position to world tile coordinates.
+func (g *Game) screenToWorld(pt image.Point) (float64, float64) {
+	return (float64(pt.X) - g.camX) / (2 * g.zoom), (float64(pt.Y) - g.camY) / (2 * g.zoom)
+}
+
+// applyAnnotationTool places a pin or note or erases at the screen position
+// pt.
+func (g *Game) applyAnnotationTool(pt image.Point) {
+	x, y := g.screenToWorld(pt)
+	kind := annotationTools[g.annotTool].kind
+	if kind == "" {
+		g.eraseAnnotation(x, y)
+		return
+	}
+	g.addAnnotation(annotation{Kind: kind, X: int(math.Floor(x)), Y: int(math.Floor(y))})
+}
+
+// annotateBarLayout holds the screen rectangles of the annotation bar.
+type annotateBarLayout struct {
+	frame image.Rectangle
+	tools []image.Rectangle
+	close image.Rectangle
+}
+
+func (g *Game) annotateBarLayout() annotateBarLayout {
+	var l annotateBarLayout
+	pad := uiScaled(6)
+	bh := menuButtonHeight()
+	tw, _ := textDimensions(AnnotateTitle)
+	w := tw + pad*2 + bh + pad
+	for _, t := range annotationTools {
+		lw, _ := textDimensions(t.label)
+		w += lw + pad*2 + pad/2
+	}
+	x := g.width/2 - w/2
+	y := max(g.asteroidInfoRect().Max.Y, uiScaled(HelpMargin)) + pad
+	l.frame = image.Rect(x, y, x+w, y+bh+pad*2)
+	bx := x + tw + pad*2
+	for _, t := range annotationTools {
+		lw, _ := textDimensions(t.label)
+		l.tools = append(l.tools, image.Rect(bx, y+pad, bx+lw+pad*2, y+pad+bh))
+		bx += lw + pad*2 + pad/2
+	}
+	l.close = image.Rect(l.frame.Max.X-pad-bh, y+pad, l.frame.Max.X-pad, y+pad+bh)
+	return l
+}
+
+// handleAnnotateInput toggles the annotation bar with N and, while it is
+// open, places annotations with the selected tool. Clicks on the map are
+// consumed; dragging without a shape tool still pans the camera.
+func (g *Game) handleAnnotateInput() bool {
+	if inpututil.IsKeyJustPressed(ebiten.KeyN) && !g.textInputActive() && len(g.asteroids) > 0 && !g.loading && !g.showSeedInput {
+		if g.annotating {
+			g.closeAnnotate()
+		} else {
+			g.openAnnotate()
+		}
+		return true
+	}
+	if !g.annotating {
+		return false
+	}
+	if g.annotEditing {
+		text := g.annotText
+		for _, r := range ebiten.AppendInputChars(nil) {
+			if utf8.RuneCountInString(text) < AnnotationTextMaxLen {
+				text += string(r)
+			}
+		}
+		if keyRepeat(ebiten.KeyBackspace) && text != "" {
+			_, size := utf8.DecodeLastRuneInString(text)
+			text = text[:len(text)-size]
+		}
+		if text != g.annotText {
+			g.annotText = text
+			g.needsRedraw = true
+		}
+		switch {
+		case inpututil.IsKeyJustPressed(ebiten.KeyEnter), inpututil.IsKeyJustPressed(ebiten.KeyNumpadEnter):
+			g.finishAnnotationText()
+		case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+			g.annotText = ""
+			g.finishAnnotationText()
+		}
+	} else if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		g.closeAnnotate()
+		return true
+	}
+
+	mx, my := ebiten.CursorPosition()
+	pt := image.Pt(mx, my)
+	if g.annotPress != nil {
+		if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+			if g.annotDrag != nil {
+				x, y := g.screenToWorld(pt)
+				g.annotDrag.X2, g.annotDrag.Y2 = int(math.Floor(x)), int(math.Floor(y))
+			} else {
+				if d := pt.Sub(*g.annotPress); d.X*d.X+d.Y*d.Y > TouchDragThreshold*TouchDragThreshold {
+					g.annotMoved = true
+				}
+				if g.annotMoved {
+					g.camX += float64(mx - g.annotLast.X)
+					g.camY += float64(my - g.annotLast.Y)
+					g.clampCamera()
+				}
+			}
+			g.annotLast = pt
+			g.needsRedraw = true
+			return true
+		}
+		switch {
+		case g.annotDrag != nil:
+			if d := *g.annotDrag; d.X2 != d.X || d.Y2 != d.Y {
+				g.addAnnotation(d)
+			}
+		case !g.annotMoved:
+			g.applyAnnotationTool(*g.annotPress)
+		}
+		g.annotPress = nil
+		g.annotDrag = nil
+		g.needsRedraw = true
+		return true
+	}
+
+	var clicks []image.Point
+	mouse := inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) && g.ssPending == 0 && g.skipClickTicks == 0
+	if mouse {
+		clicks = append(clicks, pt)
+	}
+	for _, id := range inpututil.AppendJustPressedTouchIDs(nil) {
+		x, y := ebiten.TouchPosition(id)
+		clicks = append(clicks, image.Pt(x, y))
+	}
+	l := g.annotateBarLayout()
+	for i, c := range clicks {
+		switch {
+		case c.In(l.close):
+			g.closeAnnotate()
+			return true
+		case c.In(l.frame):
+			for t, r := range l.tools {
+				if c.In(r) {
+					g.finishAnnotationText()
+					g.annotTool = t
+					g.needsRedraw = true
+				}
+			}
+			return true
+		case c.In(g.bottomTrayRect()) || c.In(g.asteroidInfoRect()):
+			continue
+		}
+		g.finishAnnotationText()
+		if i > 0 || !mouse {
+			// Touch taps place pins and notes or erase; shapes need a
+			// mouse drag.
+			if annotationTools[g.annotTool].kind != annotationRect && annotationTools[g.annotTool].kind != annotationCircle {
+				g.applyAnnotationTool(c)
+			}
+			return true
+		}
+		press := c
+		g.annotPress = &press
+		g.annotLast = c
+		g.annotMoved = false
+		if kind := annotationTools[g.annotTool].kind; kind == annotationRect || kind == annotationCircle {
+			x, y := g.screenToWorld(c)
+			tx, ty := int(math.Floor(x)), int(math.Floor(y))
+			g.annotDrag = &annotation{Kind: kind, X: tx, Y: ty, X2: tx, Y2: ty}
+		}
+		return true
+	}
+	return false
+}
+
+// drawAnnotations draws the annotations of the shown asteroid and the shape
+// being dragged. Screenshots include them only when enabled in the
+// screenshot menu.
+func (g *Game) drawAnnotations(dst *ebiten.Image) {
+	if !g.showAnnotations || (g.screenshotMode && !g.ssAnnotations) {
+		return
+	}
+	list := g.currentAnnotations()
+	editing := g.annotEditing && g.annotEditKey == annotationKey(g.coord, g.asteroidID)
+	for i, a := range list {
+		text := a.Text
+		if editing && i == g.annotEdit {
+			text = g.annotText + "_"
+		}
+		g.drawAnnotation(dst, a, text)
+	}
+	if g.annotDrag != nil {
+		g.drawAnnotation(dst, *g.annotDrag, "")
+	}
+}
+
+func (g *Game) drawAnnotation(dst *ebiten.Image, a annotation, text string) {
+	toScreen := func(x, y int) (float32, float32) {
+		return float32(float64(x)*2*g.zoom + g.camX), float32(float64(y)*2*g.zoom + g.camY)
+	}
+	x, y := toScreen(a.X, a.Y)
+	r := float32(uiScaled(6))
+	labelX, labelY := int(x), int(y)+uiScaled(4)
+	switch a.Kind {
+	case annotationPin:
+		vector.StrokeLine(dst, x, y, x, y-r*3, 2, annotationColor, true)
+		vector.DrawFilledCircle(dst, x, y-r*3, r, annotationColor, true)
+		vector.StrokeCircle(dst, x, y-r*3, r, 1, buttonBorderColor, true)
+	case annotationNote:
+		vector.DrawFilledCircle(dst, x, y, r/2, annotationColor, true)
+		if text == "" {
+			text = "_"
+		}
+	case annotationRect:
+		x2, y2 := toScreen(a.X2, a.Y2)
+		left, top := min(x, x2), min(y, y2)
+		vector.StrokeRect(dst, left, top, max(x, x2)-left, max(y, y2)-top, 2, annotationColor, true)
+		labelX, labelY = int(left+max(x, x2))/2, int(max(y, y2))+uiScaled(4)
+	case annotationCircle:
+		x2, y2 := toScreen(a.X2, a.Y2)
+		rad := float32(math.Hypot(float64(x2-x), float64(y2-y)))
+		vector.StrokeCircle(dst, x, y, rad, 2, annotationColor, true)
+		labelY = int(y+rad) + uiScaled(4)
+	}
+	if text != "" {
+		drawTextWithBGBorder(dst, text, labelX, labelY, annotationColor, true)
+	}
+}
+
+// drawAnnotateBar draws the annotation tool bar.
+func (g *Game) drawAnnotateBar(dst *ebiten.Image) {
+	if !g.annotating || g.screenshotMode {
+		return
+	}
+	l := g.annotateBarLayout()
+	pad := uiScaled(6)
+	drawFrame(dst, l.frame)
+	lh := menuButtonHeight() - 5
+	if notoFont != nil {
+		lh = notoFont.Metrics().Height.Ceil()
+	}
+	textY := func(r image.Rectangle) int { return r.Min.Y + (r.Dy()-lh)/2 }
+	drawText(dst, AnnotateTitle, l.frame.Min.X+pad, textY(l.close), false)
+	for i, r := range l.tools {
+		drawButton(dst, r, i == g.annotTool)
+		drawText(dst, annotationTools[i].label, r.Min.X+r.Dx()/2, textY(r), true)
+	}
+	drawCloseButton(dst, l.close)
+	if g.annotErr != "" {
+		drawTextWithBG(dst, g.annotErr, g.width/2, l.frame.Max.Y+pad, true)
+	}
+}
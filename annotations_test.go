@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAnnotationAt(t *testing.T) {
+	list := []annotation{
+		{Kind: annotationRect, X: 10, Y: 10, X2: 0, Y2: 0},
+		{Kind: annotationCircle, X: 50, Y: 50, X2: 53, Y2: 54},
+		{Kind: annotationPin, X: 5, Y: 5, Text: "main base"},
+	}
+	cases := []struct {
+		x, y float64
+		want int
+	}{
+		{5.5, 5.5, 2}, // the pin is above the rectangle
+		{8, 2, 0},
+		{10.5, 10.5, 0},
+		{54, 50, 1},
+		{56, 50, 1}, // within the tolerance of the edge
+		{57, 50, -1},
+		{30, 30, -1},
+	}
+	for _, c := range cases {
+		if got := annotationAt(list, c.x, c.y, 1); got != c.want {
+			t.Errorf("annotationAt(%v, %v) = %d, want %d", c.x, c.y, got, c.want)
+		}
+	}
+}
+
+func TestAnnotationStoreJSON(t *testing.T) {
+	s := annotationStore{
+		annotationKey(" sndst-a-7-0-0-0", "SandstoneDefault"): {
+			{Kind: annotationNote, X: 1, Y: 2, Text: "tame this vent first"},
+		},
+	}
+	if _, ok := s["SNDST-A-7-0-0-0/SandstoneDefault"]; !ok {
+		t.Fatalf("unexpected key in %v", s)
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var back annotationStore
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s, back) {
+		t.Errorf("round trip = %v, want %v", back, s)
+	}
+}
+
+func TestDecodeAnnotationStore(t *testing.T) {
+	cases := []struct {
+		name    string
+		data    string
+		readErr error
+		want    int
+		wantErr bool
+	}{
+		{"missing", "", fs.ErrNotExist, 0, false},
+		{"valid", `{"SNDST-A-7-0-0-0/SandstoneDefault":[{"kind":"pin","x":1,"y":2}]}`, nil, 1, false},
+		{"unreadable", "", fs.ErrPermission, 0, true},
+		{"corrupt", `{"SNDST-A-7-0-0-0/SandstoneDefault":[{"kind":`, nil, 0, true},
+	}
+	for _, c := range cases {
+		s, err := decodeAnnotationStore([]byte(c.data), c.readErr)
+		if (err != nil) != c.wantErr || len(s) != c.want {
+			t.Errorf("%s: got %d entries, error %v", c.name, len(s), err)
+		}
+	}
+}
+
+func TestSetAnnotationsAfterLoadError(t *testing.T) {
+	g := &Game{annotations: annotationStore{}, annotLoadErr: errors.New("unexpected end of JSON input")}
+	g.setAnnotations("SNDST-A-7-0-0-0/SandstoneDefault", []annotation{{Kind: annotationPin, X: 1, Y: 2}})
+	if !strings.Contains(g.annotErr, "could not be loaded") {
+		t.Errorf("annotErr = %q, want the load error", g.annotErr)
+	}
+	if len(g.annotations) != 1 {
+		t.Errorf("annotation not kept for the session: %v", g.annotations)
+	}
+}
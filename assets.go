@@ -71,6 +71,16 @@ func resolveAssetName(name string) string {
 }
 
 func loadImageFile(name string) (*ebiten.Image, error) {
+	img, err := decodeAssetImage(name)
+	if err != nil {
+		return nil, err
+	}
+	return ebiten.NewImageFromImage(img), nil
+}
+
+// decodeAssetImage decodes an embedded PNG asset. Nearly transparent pixels
+// are cleared so scaled icons don't show a faint halo.
+func decodeAssetImage(name string) (*image.NRGBA, error) {
 	resolved := resolveAssetName(name)
 	f, err := openAsset(resolved)
 	if err != nil {
@@ -88,23 +98,18 @@ func loadImageFile(name string) (*ebiten.Image, error) {
 	if err != nil {
 		return nil, err
 	}
-	if nrgba, ok := src.(*image.NRGBA); ok {
-		for i := 3; i < len(nrgba.Pix); i += 4 {
-			if nrgba.Pix[i] < 64 {
-				nrgba.Pix[i] = 0
-			}
-		}
-		return ebiten.NewImageFromImage(nrgba), nil
+	dst, ok := src.(*image.NRGBA)
+	if !ok {
+		bounds := src.Bounds()
+		dst = image.NewNRGBA(bounds)
+		draw.Draw(dst, bounds, src, bounds.Min, draw.Src)
 	}
-	bounds := src.Bounds()
-	dst := image.NewNRGBA(bounds)
-	draw.Draw(dst, bounds, src, bounds.Min, draw.Src)
 	for i := 3; i < len(dst.Pix); i += 4 {
 		if dst.Pix[i] < 64 {
 			dst.Pix[i] = 0
 		}
 	}
-	return ebiten.NewImageFromImage(dst), nil
+	return dst, nil
 }
 
 func loadBiomeTextures() map[string]*ebiten.Image {
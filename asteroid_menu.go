@@ -24,6 +24,39 @@ func (g *Game) asteroidArrowRect() image.Rectangle {
 	return image.Rect(x, y, x+size, y+size)
 }
 
+// seedLabel returns the text shown above the asteroid name, e.g.
+// "Verdante cluster, seed 1331877 (V-FRST-C-1331877-0-0-0)".
+func (g *Game) seedLabel() string {
+	if d := describeCoordinate(g.coord); d != "" {
+		return d + " (" + g.coord + ")"
+	}
+	return g.coord
+}
+
+// asteroidTraitsLabel returns the world trait line shown under the asteroid
+// name, or "" when the current asteroid has no traits.
+func (g *Game) asteroidTraitsLabel() string {
+	idx := asteroidIndexByID(g.asteroids, g.asteroidID)
+	if idx < 0 || len(g.asteroids[idx].Traits) == 0 {
+		return ""
+	}
+	return truncateString("Traits: "+worldTraitNames(g.asteroids[idx].Traits), 96)
+}
+
+func traitsBaseline() int {
+	return seedBaseline() + 2*(notoFont.Metrics().Height.Ceil()+uiScaled(4))
+}
+
+// asteroidMenuLabel returns the menu entry for a, listing its traits after
+// the name.
+func asteroidMenuLabel(a Asteroid) string {
+	label := a.ID
+	if len(a.Traits) > 0 {
+		label += " - " + worldTraitNames(a.Traits)
+	}
+	return truncateString(label, 64)
+}
+
 // asteroidInfoRect returns the bounding rectangle surrounding the seed
 // coordinate, asteroid name, and arrow. This is used as the clickable area for
 // opening the asteroid menu.
@@ -31,7 +64,7 @@ func (g *Game) asteroidInfoRect() image.Rectangle {
 	if g.coord == "" {
 		return image.Rectangle{}
 	}
-	sw, sh := textDimensions(g.coord)
+	sw, sh := textDimensions(g.seedLabel())
 	sx := g.width/2 - sw/2
 	seedRect := image.Rect(sx-uiScaled(2), seedBaseline()-uiScaled(2), sx+sw+uiScaled(2), seedBaseline()-uiScaled(2)+sh+uiScaled(4))
 
@@ -48,6 +81,11 @@ func (g *Game) asteroidInfoRect() image.Rectangle {
 
 	rect := seedRect.Union(astRect)
 	rect = rect.Union(g.asteroidArrowRect())
+	if traits := g.asteroidTraitsLabel(); traits != "" {
+		tw, th := textDimensions(traits)
+		tx := g.width/2 - tw/2
+		rect = rect.Union(image.Rect(tx-uiScaled(2), traitsBaseline()-uiScaled(2), tx+tw+uiScaled(2), traitsBaseline()-uiScaled(2)+th+uiScaled(4)))
+	}
 	return image.Rect(rect.Min.X-uiScaled(2), rect.Min.Y-uiScaled(2), rect.Max.X+uiScaled(2), rect.Max.Y+uiScaled(2))
 }
 
@@ -90,11 +128,38 @@ func drawCheck(dst *ebiten.Image, rect image.Rectangle) {
 	vector.StrokeLine(dst, x1, y1, x2, y2, thickness, color.White, true)
 }
 
+// menuAction is a button below the asteroid list.
+type menuAction struct {
+	label string
+	run   func()
+}
+
+func (g *Game) asteroidMenuActions() []menuAction {
+	return []menuAction{
+		{ChangeSeedLabel, g.openSeedInput},
+		{ClusterMapLabel, g.openClusterMap},
+		{ScoreLabel, g.openScorePanel},
+		{FindLabel, g.openFind},
+		{AnnotateLabel, g.openAnnotate},
+		{CompareLabel, g.openCompareInput},
+		{ShareLabel, g.shareView},
+		{ExportJSONLabel, func() { g.exportSeedData("json", false) }},
+		{ExportCSVLabel, func() { g.exportSeedData("csv", false) }},
+		{ExportAsteroidJSONLabel, func() { g.exportSeedData("json", true) }},
+		{ExportAsteroidCSVLabel, func() { g.exportSeedData("csv", true) }},
+	}
+}
+
 func (g *Game) asteroidMenuSize() (int, int) {
 	maxW, _ := textDimensions(AsteroidMenuTitle)
+	actions := g.asteroidMenuActions()
+	for _, act := range actions {
+		if w, _ := textDimensions(act.label); w > maxW {
+			maxW = w
+		}
+	}
 	for _, a := range g.asteroids {
-		name := truncateString(a.ID, 64)
-		w, _ := textDimensions(name)
+		w, _ := textDimensions(asteroidMenuLabel(a))
 		if w > maxW {
 			maxW = w
 		}
@@ -103,7 +168,7 @@ func (g *Game) asteroidMenuSize() (int, int) {
 	// longer names don't butt up against the right edge of the menu.
 	// Include an extra character width of padding for clarity.
 	w := maxW + uiScaled(28) + LabelCharWidth
-	h := (len(g.asteroids)+1)*menuSpacing() + uiScaled(4)
+	h := (len(g.asteroids)+len(actions)+1)*menuSpacing() + uiScaled(4)
 	return w, h
 }
 
@@ -118,6 +183,9 @@ func (g *Game) asteroidMenuRect() image.Rectangle {
 		x = g.width - w
 	}
 	y := ar.Max.Y + uiScaled(4)
+	if g.asteroidTraitsLabel() != "" {
+		y = g.asteroidInfoRect().Max.Y + uiScaled(2)
+	}
 	if y+h > g.height {
 		y = g.height - h
 	}
@@ -159,8 +227,13 @@ func (g *Game) drawAsteroidMenu(dst *ebiten.Image) {
 			ck := image.Rect(btn.Min.X+uiScaled(4), btn.Min.Y+uiScaled(4), btn.Min.X+uiScaled(16), btn.Min.Y+uiScaled(16))
 			drawCheck(img, ck)
 		}
-		name := truncateString(a.ID, 64)
-		drawText(img, name, btn.Min.X+uiScaled(20), btn.Min.Y+uiScaled(4), false)
+		drawText(img, asteroidMenuLabel(a), btn.Min.X+uiScaled(20), btn.Min.Y+uiScaled(4), false)
+		y += menuSpacing()
+	}
+	for _, act := range g.asteroidMenuActions() {
+		btn := image.Rect(uiScaled(4), y-uiScaled(4), w-uiScaled(4), y-uiScaled(4)+menuButtonHeight())
+		drawButton(img, btn, true)
+		drawText(img, act.label, btn.Min.X+uiScaled(20), btn.Min.Y+uiScaled(4), false)
 		y += menuSpacing()
 	}
 	op := &ebiten.DrawImageOptions{}
@@ -193,47 +266,24 @@ func (g *Game) clickAsteroidMenu(mx, my int) bool {
 		}
 		yPos += menuSpacing()
 	}
+	for _, act := range g.asteroidMenuActions() {
+		r := image.Rect(uiScaled(4), yPos-uiScaled(4), w-uiScaled(4), yPos-uiScaled(4)+menuButtonHeight())
+		if r.Overlaps(image.Rect(mx, my, mx+1, my+1)) {
+			g.showAstMenu = false
+			g.asteroidScroll = 0
+			act.run()
+			g.needsRedraw = true
+			return true
+		}
+		yPos += menuSpacing()
+	}
 	return true
 }
 
 func (g *Game) loadAsteroid(ast Asteroid) {
-	g.invalidateLegends()
-	g.asteroidID = ast.ID
-	// Reset cached legends so item numbering matches the newly loaded
-	// asteroid contents. These are rebuilt lazily when needed.
-	g.legendMap = nil
-	g.legendEntries = nil
-	g.legendColors = nil
-	g.selectedItem = -1
-	g.itemScroll = 0
-	bps := ast.BiomePaths.Paths
-	g.geysers = ast.Geysers
-	g.pois = ast.POIs
-	g.biomes = bps
-	g.astWidth = ast.SizeX
-	g.astHeight = ast.SizeY
-	g.legend, g.legendBiomes = buildLegendImage(bps)
-	g.fitOnLoad = true
+	g.setAsteroid(ast)
 	g.biomeTextures = loadBiomeTextures()
-	names := []string{"../icons/camera.png", "../icons/help.png", "../icons/gear.png", "geyser_water.png"}
-	set := make(map[string]struct{})
-	for _, gy := range ast.Geysers {
-		if n := iconForGeyser(gy.ID); n != "" {
-			if _, ok := set[n]; !ok {
-				set[n] = struct{}{}
-				names = append(names, n)
-			}
-		}
-	}
-	for _, poi := range ast.POIs {
-		if n := iconForPOI(poi.ID); n != "" {
-			if _, ok := set[n]; !ok {
-				set[n] = struct{}{}
-				names = append(names, n)
-			}
-		}
-	}
-	g.startIconLoader(names)
+	g.startIconLoader(iconNamesForAsteroid(ast))
 }
 
 func (g *Game) centerAndFit() {
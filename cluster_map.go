@@ -0,0 +1,209 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// clusterMapPlacement returns the position of each asteroid in world cells.
+// Asteroids are placed at their cluster offsets; clusters without offsets,
+// such as base game seeds, fall back to a simple grid.
+func clusterMapPlacement(asts []Asteroid) []image.Rectangle {
+	rects := make([]image.Rectangle, len(asts))
+	useOffsets := false
+	for _, a := range asts {
+		if a.OffsetX != 0 || a.OffsetY != 0 {
+			useOffsets = true
+			break
+		}
+	}
+	if useOffsets {
+		for i, a := range asts {
+			rects[i] = image.Rect(a.OffsetX, a.OffsetY, a.OffsetX+a.SizeX, a.OffsetY+a.SizeY)
+		}
+		return rects
+	}
+	cols := int(math.Ceil(math.Sqrt(float64(len(asts)))))
+	cellW, cellH := 0, 0
+	for _, a := range asts {
+		cellW = max(cellW, a.SizeX)
+		cellH = max(cellH, a.SizeY)
+	}
+	gap := ClusterMapGap
+	for i, a := range asts {
+		x := (i % cols) * (cellW + gap)
+		y := (i / cols) * (cellH + gap)
+		rects[i] = image.Rect(x, y, x+a.SizeX, y+a.SizeY)
+	}
+	return rects
+}
+
+// clusterMapRects scales the asteroid placement to fit the window below the
+// title, leaving room for a label under each thumbnail.
+func (g *Game) clusterMapRects() []image.Rectangle {
+	world := clusterMapPlacement(g.asteroids)
+	if len(world) == 0 {
+		return nil
+	}
+	bounds := world[0]
+	for _, r := range world[1:] {
+		bounds = bounds.Union(r)
+	}
+	margin := uiScaled(ClusterMapMargin)
+	top := margin + menuSpacing()
+	labelH := 2 * menuSpacing()
+	availW := float64(g.width - 2*margin)
+	availH := float64(g.height - top - margin - labelH)
+	if availW <= 0 || availH <= 0 || bounds.Dx() == 0 || bounds.Dy() == 0 {
+		return make([]image.Rectangle, len(world))
+	}
+	scale := math.Min(availW/float64(bounds.Dx()), availH/float64(bounds.Dy()))
+	offX := margin + int((availW-float64(bounds.Dx())*scale)/2)
+	offY := top + int((availH-float64(bounds.Dy())*scale)/2)
+	rects := make([]image.Rectangle, len(world))
+	for i, r := range world {
+		x0 := offX + int(float64(r.Min.X-bounds.Min.X)*scale)
+		y0 := offY + int(float64(r.Min.Y-bounds.Min.Y)*scale)
+		x1 := offX + int(float64(r.Max.X-bounds.Min.X)*scale)
+		y1 := offY + int(float64(r.Max.Y-bounds.Min.Y)*scale)
+		rects[i] = image.Rect(x0, y0, x1, y1)
+	}
+	return rects
+}
+
+// clusterThumbnail returns a cached flat-colored rendering of the biomes of
+// a, at most ClusterThumbSize pixels on its longest side.
+func (g *Game) clusterThumbnail(a Asteroid) *ebiten.Image {
+	if img, ok := g.clusterThumbs[a.ID]; ok {
+		return img
+	}
+	if a.SizeX == 0 || a.SizeY == 0 {
+		return nil
+	}
+	scale := float64(ClusterThumbSize) / float64(max(a.SizeX, a.SizeY))
+	w := max(1, int(float64(a.SizeX)*scale))
+	h := max(1, int(float64(a.SizeY)*scale))
+	img := ebiten.NewImage(w, h)
+	img.Fill(biomeColors["Space"])
+	// drawBiome works in half-cell units, so halve the scale for zoom.
+	zoom := scale / 2
+	for _, bp := range a.BiomePaths.Paths {
+		clr, ok := biomeColors[bp.Name]
+		if !ok {
+			clr = color.RGBA{60, 60, 60, 255}
+		}
+		drawBiome(img, bp.Polygons, clr, 0, 0, zoom)
+	}
+	if g.clusterThumbs == nil {
+		g.clusterThumbs = make(map[string]*ebiten.Image)
+	}
+	g.clusterThumbs[a.ID] = img
+	return img
+}
+
+func (g *Game) openClusterMap() {
+	g.closeMenus()
+	g.showClusterMap = true
+	g.needsRedraw = true
+}
+
+func (g *Game) closeClusterMap() {
+	g.showClusterMap = false
+	g.needsRedraw = true
+}
+
+func (g *Game) clusterCloseRect() image.Rectangle {
+	return g.geyserCloseRect()
+}
+
+// handleClusterMapInput toggles the cluster map with the M key and handles
+// clicks on asteroid thumbnails. It returns true while the map is shown.
+func (g *Game) handleClusterMapInput() bool {
+	if inpututil.IsKeyJustPressed(ebiten.KeyM) && len(g.asteroids) > 0 && !g.loading && !g.textInputActive() {
+		if g.showClusterMap {
+			g.closeClusterMap()
+		} else {
+			g.openClusterMap()
+		}
+		return true
+	}
+	if !g.showClusterMap {
+		return false
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		g.closeClusterMap()
+		return true
+	}
+	var clicks []image.Point
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		mx, my := ebiten.CursorPosition()
+		clicks = append(clicks, image.Pt(mx, my))
+	}
+	for _, id := range inpututil.AppendJustPressedTouchIDs(nil) {
+		x, y := ebiten.TouchPosition(id)
+		clicks = append(clicks, image.Pt(x, y))
+	}
+	for _, pt := range clicks {
+		g.clickClusterMap(pt)
+	}
+	return true
+}
+
+func (g *Game) clickClusterMap(pt image.Point) {
+	if pt.In(g.clusterCloseRect()) {
+		g.closeClusterMap()
+		return
+	}
+	for i, r := range g.clusterMapRects() {
+		if i < len(g.asteroids) && pt.In(r) {
+			g.closeClusterMap()
+			if g.asteroids[i].ID != g.asteroidID {
+				g.loadAsteroid(g.asteroids[i])
+			}
+			return
+		}
+	}
+}
+
+// drawClusterMapScreen draws the cluster overview in place of the map view.
+func (g *Game) drawClusterMapScreen(dst *ebiten.Image) bool {
+	if !g.showClusterMap {
+		return false
+	}
+	dst.Fill(backgroundColor)
+	margin := uiScaled(ClusterMapMargin)
+	title := ClusterMapTitle
+	if d := describeCoordinate(g.coord); d != "" {
+		title += " " + d
+	}
+	drawText(dst, title, g.width/2, margin, true)
+	for i, r := range g.clusterMapRects() {
+		a := g.asteroids[i]
+		if thumb := g.clusterThumbnail(a); thumb != nil && !r.Empty() {
+			op := &ebiten.DrawImageOptions{Filter: ebiten.FilterLinear}
+			op.GeoM.Scale(float64(r.Dx())/float64(thumb.Bounds().Dx()), float64(r.Dy())/float64(thumb.Bounds().Dy()))
+			op.GeoM.Translate(float64(r.Min.X), float64(r.Min.Y))
+			dst.DrawImage(thumb, op)
+		}
+		var border color.Color = buttonBorderColor
+		width := float32(1)
+		if a.ID == g.asteroidID {
+			border = scrollBarColor
+			width = 3
+		}
+		vector.StrokeRect(dst, float32(r.Min.X), float32(r.Min.Y), float32(r.Dx()), float32(r.Dy()), width, border, false)
+		label := fmt.Sprintf("%s\n%d geysers", truncateString(a.ID, 32), len(a.Geysers))
+		drawTextWithBG(dst, label, r.Min.X+r.Dx()/2, r.Max.Y+uiScaled(4), true)
+	}
+	drawCloseButton(dst, g.clusterCloseRect())
+	g.needsRedraw = false
+	g.lastDraw = time.Now()
+	return true
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"image"
+	"reflect"
+	"testing"
+)
+
+func TestClusterMapPlacement(t *testing.T) {
+	gap := ClusterMapGap
+	tests := []struct {
+		name string
+		asts []Asteroid
+		want []image.Rectangle
+	}{
+		{
+			// The starting asteroid sits at the origin; any other offset
+			// selects the cluster layout.
+			"offsets",
+			[]Asteroid{
+				{SizeX: 160, SizeY: 274},
+				{SizeX: 64, SizeY: 96, OffsetX: 200, OffsetY: 40},
+				{SizeX: 80, SizeY: 80, OffsetX: -120, OffsetY: 300},
+			},
+			[]image.Rectangle{
+				image.Rect(0, 0, 160, 274),
+				image.Rect(200, 40, 264, 136),
+				image.Rect(-120, 300, -40, 380),
+			},
+		},
+		{
+			// Without offsets the asteroids fill a square grid of cells as
+			// large as the largest asteroid.
+			"grid",
+			[]Asteroid{
+				{SizeX: 160, SizeY: 274},
+				{SizeX: 64, SizeY: 96},
+				{SizeX: 80, SizeY: 80},
+			},
+			[]image.Rectangle{
+				image.Rect(0, 0, 160, 274),
+				image.Rect(160+gap, 0, 160+gap+64, 96),
+				image.Rect(0, 274+gap, 80, 274+gap+80),
+			},
+		},
+		{
+			"single base game asteroid",
+			[]Asteroid{{SizeX: 256, SizeY: 384}},
+			[]image.Rectangle{image.Rect(0, 0, 256, 384)},
+		},
+		{"empty", nil, []image.Rectangle{}},
+	}
+	for _, tt := range tests {
+		if got := clusterMapPlacement(tt.asts); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// commands maps subcommand names to their entry points. Each receives the
+// arguments following the subcommand name and returns the process exit code.
+var commands = map[string]func(args []string) int{
+	"diff":   runDiff,
+	"export": runExport,
+	"render": runRender,
+	"score":  runScore,
+	"search": runSearch,
+}
+
+// runCommand runs the subcommand named by args[0]. ok is false when args does
+// not start with a known subcommand and the viewer should start instead.
+func runCommand(args []string) (code int, ok bool) {
+	if len(args) == 0 {
+		return 0, false
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return 0, false
+	}
+	return cmd(args[1:]), true
+}
+
+// commandNames lists the available subcommands for usage messages.
+func commandNames() string {
+	list := make([]string, 0, len(commands))
+	for name := range commands {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+	return strings.Join(list, ", ")
+}
+
+// seedSourceFlags are the seed download and cache flags shared by the viewer
+// and subcommands.
+type seedSourceFlags struct {
+	cacheDir string
+}
+
+func addSeedSourceFlags(fs *flag.FlagSet) *seedSourceFlags {
+	f := &seedSourceFlags{}
+	fs.BoolVar(&offlineMode, "offline", false, "only load seeds from the local cache")
+	fs.BoolVar(&refreshSeedCache, "refresh", false, "revalidate cached seeds with the server")
+	fs.StringVar(&f.cacheDir, "cache-dir", seedProtoCache.dir, "directory for cached seed data (empty disables caching)")
+	fs.Func("timeout", fmt.Sprintf("timeout for each seed download attempt (default %v)", seedProtoFetchPolicy.Timeout), func(s string) error {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		if d <= 0 {
+			return fmt.Errorf("must be positive")
+		}
+		seedProtoFetchPolicy.Timeout = d
+		return nil
+	})
+	fs.IntVar(&seedProtoFetchPolicy.MaxRetries, "retries", seedProtoFetchPolicy.MaxRetries, "number of retries for failed seed downloads")
+	return f
+}
+
+// apply installs the parsed flag values. Call it after parsing.
+func (f *seedSourceFlags) apply() {
+	seedProtoCache = newSeedCache(f.cacheDir)
+}
+
+// newCommandFlags returns a flag set for a subcommand that prints usage
+// and the flag defaults to stderr.
+func newCommandFlags(name, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s %s\n", os.Args[0], usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// seedSource is a coordinate or local seed file named on a command line.
+// Arguments ending in .pb or .pb.gz are treated as files.
+type seedSource struct {
+	coord string
+	file  string
+}
+
+func parseSeedSource(arg string) seedSource {
+	lower := strings.ToLower(arg)
+	if strings.HasSuffix(lower, ".pb") || strings.HasSuffix(lower, ".pb.gz") {
+		return seedSource{coord: coordFromFileName(arg), file: arg}
+	}
+	return seedSource{coord: strings.ToUpper(strings.TrimSpace(arg))}
+}
+
+// loadSeed loads and decodes the seed for src without any UI.
+func loadSeed(ctx context.Context, src seedSource) (*SeedData, error) {
+	data, err := loadSeedData(ctx, src.coord, src.file, nil)
+	if err != nil {
+		return nil, err
+	}
+	return decodeSeedProto(data)
+}
+
+// selectAsteroids picks asteroids from seed by ID. An empty list selects the
+// starting asteroid and "all" selects every asteroid.
+func selectAsteroids(seed *SeedData, ids []string) ([]Asteroid, error) {
+	if len(seed.Asteroids) == 0 {
+		return nil, fmt.Errorf("seed contains no asteroids")
+	}
+	if len(ids) == 0 {
+		return seed.Asteroids[:1], nil
+	}
+	var out []Asteroid
+	for _, id := range ids {
+		if strings.EqualFold(id, "all") {
+			return seed.Asteroids, nil
+		}
+		idx := asteroidIndexByID(seed.Asteroids, normalizeAsteroidID(id))
+		if idx < 0 {
+			valid := make([]string, len(seed.Asteroids))
+			for i, a := range seed.Asteroids {
+				valid[i] = a.ID
+			}
+			return nil, fmt.Errorf("no asteroid %q (valid IDs: %s)", id, strings.Join(valid, ", "))
+		}
+		out = append(out, seed.Asteroids[idx])
+	}
+	return out, nil
+}
+
+// splitList splits a comma separated flag value, dropping empty entries.
+func splitList(s string) []string {
+	var out []string
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// commandError prints err prefixed with the command name.
+func commandError(w io.Writer, cmd string, err error) {
+	fmt.Fprintf(w, "%s: %v\n", cmd, err)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// compareRow is one line of the comparison table.
+type compareRow struct {
+	Label       string
+	Left, Right string
+}
+
+// geyserTotals counts the geysers of each type in a and sums their effective
+// output per cycle in kg.
+func geyserTotals(a Asteroid) (counts map[string]int, output map[string]float64) {
+	counts = make(map[string]int)
+	output = make(map[string]float64)
+	for _, gy := range a.Geysers {
+		id := simplifyID(gy.ID)
+		counts[id]++
+		output[id] += gy.effectiveOutputPerCycle()
+	}
+	return counts, output
+}
+
+// compareAsteroids builds the comparison table of a and b: general facts,
+// the score against rules when given, then one row per geyser type found on
+// either asteroid with its count and the mean effective output per geyser,
+// followed by the total when there are several.
+func compareAsteroids(a, b Asteroid, rules *scoreRuleSet, aCluster, bCluster []Asteroid) []compareRow {
+	traits := func(ast Asteroid) string {
+		if len(ast.Traits) == 0 {
+			return "none"
+		}
+		return worldTraitNames(ast.Traits)
+	}
+	size := func(ast Asteroid) string { return fmt.Sprintf("%dx%d", ast.SizeX, ast.SizeY) }
+	rows := []compareRow{
+		{"Asteroid", a.ID, b.ID},
+		{"Size", size(a), size(b)},
+		{"Traits", traits(a), traits(b)},
+		{"Geysers", strconv.Itoa(len(a.Geysers)), strconv.Itoa(len(b.Geysers))},
+		{"POIs", strconv.Itoa(len(a.POIs)), strconv.Itoa(len(b.POIs))},
+	}
+	if rules != nil {
+		ra := rules.evaluate(aCluster, a)
+		rb := rules.evaluate(bCluster, b)
+		rows = append(rows, compareRow{"Score", ra.summary(), rb.summary()})
+	}
+	ca, oa := geyserTotals(a)
+	cb, ob := geyserTotals(b)
+	var ids []string
+	for id := range ca {
+		ids = append(ids, id)
+	}
+	for id := range cb {
+		if _, ok := ca[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return exportGeyserName(ids[i]) < exportGeyserName(ids[j]) })
+	cell := func(n int, kg float64) string {
+		switch n {
+		case 0:
+			return "-"
+		case 1:
+			return fmt.Sprintf("1: %s kg/cycle", formatNum(math.Round(kg)))
+		}
+		return fmt.Sprintf("%d: avg %s kg/cycle (%s total)", n, formatNum(math.Round(kg/float64(n))), formatNum(math.Round(kg)))
+	}
+	for _, id := range ids {
+		rows = append(rows, compareRow{exportGeyserName(id), cell(ca[id], oa[id]), cell(cb[id], ob[id])})
+	}
+	return rows
+}
@@ -0,0 +1,36 @@
+package main
+
+import "testing"
+
+func TestCompareAsteroids(t *testing.T) {
+	steam := Geyser{ID: "steam", EmitRate: 3000, EruptionTime: 150, IdleTime: 450, ActiveCycles: 60, DormancyCycles: 40}
+	// A second, always active steam vent emitting 90 kg/cycle.
+	weakSteam := Geyser{ID: "steam", EmitRate: 1000, EruptionTime: 90, IdleTime: 510, ActiveCycles: 100}
+	a := Asteroid{ID: "A", SizeX: 240, SizeY: 380, Traits: []string{"traits/MetalPoor"}, Geysers: []Geyser{steam, weakSteam}}
+	b := Asteroid{ID: "B", SizeX: 160, SizeY: 274, Geysers: []Geyser{{ID: "molten_gold"}}}
+	rows := compareAsteroids(a, b, nil, nil, nil)
+	find := func(label string) compareRow {
+		for _, r := range rows {
+			if r.Label == label {
+				return r
+			}
+		}
+		t.Fatalf("no %q row in %+v", label, rows)
+		return compareRow{}
+	}
+	if r := find("Size"); r.Left != "240x380" || r.Right != "160x274" {
+		t.Errorf("Size row = %+v", r)
+	}
+	if r := find("Traits"); r.Right != "none" {
+		t.Errorf("Traits row = %+v", r)
+	}
+	if r := find("Geysers"); r.Left != "2" || r.Right != "1" {
+		t.Errorf("Geysers row = %+v", r)
+	}
+	if r := find(exportGeyserName("steam")); r.Left != "2: avg 180 kg/cycle (360 total)" || r.Right != "-" {
+		t.Errorf("steam row = %+v", r)
+	}
+	if r := find(exportGeyserName("molten_gold")); r.Left != "-" || r.Right != "1: 0 kg/cycle" {
+		t.Errorf("gold row = %+v", r)
+	}
+}
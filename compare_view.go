@@ -0,0 +1,527 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// comparePane is one side of the comparison view.
+type comparePane struct {
+	coord      string
+	asteroids  []Asteroid
+	asteroidID string
+	// camX and camY place the asteroid origin relative to the pane.
+	camX, camY float64
+}
+
+func (p *comparePane) asteroid() (Asteroid, bool) {
+	idx := asteroidIndexByID(p.asteroids, p.asteroidID)
+	if idx < 0 {
+		return Asteroid{}, false
+	}
+	return p.asteroids[idx], true
+}
+
+// compareView holds the state of the split-screen comparison. Both panes
+// share one zoom level; when linked they also share the camera position.
+type compareView struct {
+	panes       [2]comparePane
+	zoom        float64
+	linked      bool
+	showTable   bool
+	tableScroll float64
+	rows        []compareRow
+	// load fetches the seed of the right pane when it differs from the
+	// loaded one.
+	load   *seedLoad
+	status string
+
+	dragPane     int
+	lastX, lastY int
+	touchID      ebiten.TouchID
+	touching     bool
+}
+
+// compareLayout holds the screen rectangles of the comparison view.
+type compareLayout struct {
+	bar, link, table, close image.Rectangle
+	panes                   [2]image.Rectangle
+}
+
+func (g *Game) compareLayout() compareLayout {
+	var l compareLayout
+	pad := uiScaled(6)
+	bh := menuButtonHeight()
+	l.bar = image.Rect(0, 0, g.width, bh+pad*2)
+	l.close = image.Rect(g.width-pad-bh, pad, g.width-pad, pad+bh)
+	x := pad
+	button := func(label string) image.Rectangle {
+		tw, _ := textDimensions(label)
+		r := image.Rect(x, pad, x+tw+pad*2, pad+bh)
+		x = r.Max.X + pad
+		return r
+	}
+	l.link = button(CompareLinkLabel)
+	l.table = button(CompareTableLabel)
+	half := g.width / 2
+	l.panes[0] = image.Rect(0, l.bar.Max.Y, half-1, g.height)
+	l.panes[1] = image.Rect(half+1, l.bar.Max.Y, g.width, g.height)
+	return l
+}
+
+// openCompareInput asks for the coordinate to compare the current asteroid
+// with.
+func (g *Game) openCompareInput() {
+	g.openSeedInput()
+	g.compareInput = true
+}
+
+// openCompare shows the current asteroid next to the starting asteroid of
+// coord. When coord is the loaded seed, the next asteroid of the cluster is
+// shown instead.
+func (g *Game) openCompare(coord string) {
+	g.closeMenus()
+	cv := &compareView{zoom: g.zoom, linked: true, dragPane: -1}
+	cv.panes[0] = comparePane{coord: g.coord, asteroids: g.asteroids, asteroidID: g.asteroidID}
+	if coord == "" || strings.EqualFold(coord, g.coord) {
+		right := cv.panes[0]
+		if idx := asteroidIndexByID(g.asteroids, g.asteroidID); idx >= 0 {
+			right.asteroidID = g.asteroids[(idx+1)%len(g.asteroids)].ID
+		}
+		cv.panes[1] = right
+	} else {
+		cv.panes[1] = comparePane{coord: coord}
+		cv.load = startSeedLoad(coord, "", false, "")
+		cv.status = "Fetching..."
+	}
+	g.compare = cv
+	g.showCompare = true
+	g.fitCompare()
+	g.needsRedraw = true
+}
+
+func (g *Game) closeCompare() {
+	if g.compare != nil && g.compare.load != nil {
+		g.compare.load.cancel()
+	}
+	g.compare = nil
+	g.showCompare = false
+	g.needsRedraw = true
+}
+
+// pollCompareLoad installs the right pane's seed once it has loaded.
+func (g *Game) pollCompareLoad() {
+	cv := g.compare
+	l := cv.load
+	if l == nil {
+		return
+	}
+	select {
+	case res := <-l.done:
+		cv.load = nil
+		l.cancel()
+		switch {
+		case res.err != nil:
+			cv.status = "Error: " + res.err.Error()
+		case len(res.seed.Asteroids) == 0:
+			cv.status = l.coord + " does not contain any asteroids"
+		default:
+			for name, img := range res.icons {
+				g.icons[name] = img
+			}
+			if g.biomeTextures == nil {
+				g.biomeTextures = res.textures
+			}
+			p := &cv.panes[1]
+			p.asteroids = res.seed.Asteroids
+			p.asteroidID = res.seed.Asteroids[0].ID
+			cv.status = ""
+			cv.rows = nil
+			g.fitCompare()
+		}
+	default:
+		msg, _ := l.progress()
+		cv.status = l.coord + "\n" + msg
+	}
+	g.needsRedraw = true
+}
+
+// fitCompare picks a zoom that fits both asteroids in their panes and
+// centers them.
+func (g *Game) fitCompare() {
+	cv := g.compare
+	l := g.compareLayout()
+	zoom := 0.0
+	for i := range cv.panes {
+		a, ok := cv.panes[i].asteroid()
+		if !ok || a.SizeX == 0 || a.SizeY == 0 {
+			continue
+		}
+		r := l.panes[i]
+		z := math.Min(float64(r.Dx())/(float64(a.SizeX)*2), float64(r.Dy())/(float64(a.SizeY)*2))
+		if zoom == 0 || z < zoom {
+			zoom = z
+		}
+	}
+	if zoom > 0 {
+		cv.zoom = zoom
+	}
+	for i := range cv.panes {
+		p := &cv.panes[i]
+		a, _ := p.asteroid()
+		r := l.panes[i]
+		p.camX = (float64(r.Dx()) - float64(a.SizeX)*2*cv.zoom) / 2
+		p.camY = (float64(r.Dy()) - float64(a.SizeY)*2*cv.zoom) / 2
+	}
+	if cv.linked {
+		cv.panes[1].camX, cv.panes[1].camY = cv.panes[0].camX, cv.panes[0].camY
+	}
+}
+
+// comparePaneAt returns the pane under pt, or -1.
+func (g *Game) comparePaneAt(pt image.Point) int {
+	for i, r := range g.compareLayout().panes {
+		if pt.In(r) {
+			return i
+		}
+	}
+	return -1
+}
+
+// panCompare moves pane i, or both panes when linked.
+func (g *Game) panCompare(i int, dx, dy float64) {
+	cv := g.compare
+	for j := range cv.panes {
+		if j == i || cv.linked {
+			cv.panes[j].camX += dx
+			cv.panes[j].camY += dy
+		}
+	}
+	g.needsRedraw = true
+}
+
+// zoomCompare changes the shared zoom by factor, keeping the world point
+// under pt fixed in pane i. Other panes zoom around the same pane position
+// when linked and around their center otherwise.
+func (g *Game) zoomCompare(i int, factor float64, pt image.Point) {
+	cv := g.compare
+	l := g.compareLayout()
+	old := cv.zoom
+	cv.zoom = math.Min(math.Max(cv.zoom*factor, MinZoom), MaxZoom)
+	for j := range cv.panes {
+		r := l.panes[j]
+		ax, ay := float64(r.Dx())/2, float64(r.Dy())/2
+		if i >= 0 && (j == i || cv.linked) {
+			ax, ay = float64(pt.X-l.panes[i].Min.X), float64(pt.Y-l.panes[i].Min.Y)
+		}
+		p := &cv.panes[j]
+		p.camX = ax - (ax-p.camX)*cv.zoom/old
+		p.camY = ay - (ay-p.camY)*cv.zoom/old
+	}
+	g.needsRedraw = true
+}
+
+// cycleCompareAsteroid shows the next asteroid of pane i's cluster.
+func (g *Game) cycleCompareAsteroid(i int) {
+	cv := g.compare
+	p := &cv.panes[i]
+	if len(p.asteroids) < 2 {
+		return
+	}
+	idx := asteroidIndexByID(p.asteroids, p.asteroidID)
+	a := p.asteroids[(idx+1)%len(p.asteroids)]
+	p.asteroidID = a.ID
+	g.startIconLoader(iconNamesForAsteroid(a))
+	cv.rows = nil
+	g.fitCompare()
+}
+
+// compareRows returns the cached comparison table.
+func (g *Game) compareRows() []compareRow {
+	cv := g.compare
+	if cv.rows != nil {
+		return cv.rows
+	}
+	a, okA := cv.panes[0].asteroid()
+	b, okB := cv.panes[1].asteroid()
+	if !okA || !okB {
+		return nil
+	}
+	var rules *scoreRuleSet
+	if rs, err := loadScoreRules(scoreRulesPath); err == nil {
+		rules = &rs
+	}
+	cv.rows = append([]compareRow{{"Seed", cv.panes[0].coord, cv.panes[1].coord}},
+		compareAsteroids(a, b, rules, cv.panes[0].asteroids, cv.panes[1].asteroids)...)
+	return cv.rows
+}
+
+func (g *Game) maxCompareTableScroll() float64 {
+	h := (len(g.compareRows())+1)*rowSpacing() + uiScaled(ClusterMapMargin)*2
+	return max(0, float64(h-g.compareLayout().panes[0].Dy()))
+}
+
+// handleCompareInput handles panning, zooming and the toolbar of the
+// comparison view. It returns true while the view is shown.
+func (g *Game) handleCompareInput() bool {
+	if !g.showCompare || g.compare == nil {
+		return false
+	}
+	cv := g.compare
+	g.pollCompareLoad()
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		if cv.showTable {
+			cv.showTable = false
+			g.needsRedraw = true
+		} else {
+			g.closeCompare()
+		}
+		return true
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyT) {
+		cv.showTable = !cv.showTable
+		cv.tableScroll = 0
+		g.needsRedraw = true
+	}
+	l := g.compareLayout()
+	mx, my := ebiten.CursorPosition()
+	cursor := image.Pt(mx, my)
+	hover := g.comparePaneAt(cursor)
+
+	// Keyboard panning and zoom apply to the pane under the cursor, or to
+	// both when linked.
+	dx, dy := 0.0, 0.0
+	if ebiten.IsKeyPressed(ebiten.KeyLeft) || ebiten.IsKeyPressed(ebiten.KeyA) {
+		dx += PanSpeed
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyRight) || ebiten.IsKeyPressed(ebiten.KeyD) {
+		dx -= PanSpeed
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyUp) || ebiten.IsKeyPressed(ebiten.KeyW) {
+		dy += PanSpeed
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyDown) || ebiten.IsKeyPressed(ebiten.KeyS) {
+		dy -= PanSpeed
+	}
+	if dx != 0 || dy != 0 {
+		g.panCompare(max(hover, 0), dx, dy)
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyEqual) || ebiten.IsKeyPressed(ebiten.KeyKPAdd) {
+		g.zoomCompare(-1, KeyZoomFactor, cursor)
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyMinus) || ebiten.IsKeyPressed(ebiten.KeyKPSubtract) {
+		g.zoomCompare(-1, 1/KeyZoomFactor, cursor)
+	}
+	if _, wheelY := ebiten.Wheel(); wheelY != 0 {
+		if cv.showTable {
+			cv.tableScroll = min(max(0, cv.tableScroll-wheelY*10), g.maxCompareTableScroll())
+			g.needsRedraw = true
+		} else if hover >= 0 {
+			g.zoomCompare(hover, math.Pow(WheelZoomFactor, wheelY), cursor)
+		}
+	}
+
+	var clicks []image.Point
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		clicks = append(clicks, cursor)
+		cv.dragPane = hover
+		cv.lastX, cv.lastY = mx, my
+	}
+	for _, id := range inpututil.AppendJustPressedTouchIDs(nil) {
+		x, y := ebiten.TouchPosition(id)
+		clicks = append(clicks, image.Pt(x, y))
+		cv.touchID, cv.touching = id, true
+		cv.dragPane = g.comparePaneAt(image.Pt(x, y))
+		cv.lastX, cv.lastY = x, y
+	}
+	for _, pt := range clicks {
+		switch {
+		case pt.In(l.close):
+			g.closeCompare()
+			return true
+		case pt.In(l.link):
+			cv.linked = !cv.linked
+			if cv.linked {
+				cv.panes[1].camX, cv.panes[1].camY = cv.panes[0].camX, cv.panes[0].camY
+			}
+			g.needsRedraw = true
+		case pt.In(l.table):
+			cv.showTable = !cv.showTable
+			cv.tableScroll = 0
+			g.needsRedraw = true
+		case pt.In(l.bar) || cv.showTable:
+			cv.dragPane = -1
+		default:
+			if i := g.comparePaneAt(pt); i >= 0 && pt.Y < l.panes[i].Min.Y+rowSpacing()+uiScaled(8) {
+				g.cycleCompareAsteroid(i)
+				cv.dragPane = -1
+			}
+		}
+	}
+
+	// Dragging pans the pane where the drag started.
+	x, y, pressed := mx, my, ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft)
+	if cv.touching {
+		if inpututil.IsTouchJustReleased(cv.touchID) {
+			cv.touching = false
+			pressed = false
+		} else {
+			x, y = ebiten.TouchPosition(cv.touchID)
+			pressed = true
+		}
+	}
+	if pressed && cv.dragPane >= 0 {
+		if x != cv.lastX || y != cv.lastY {
+			g.panCompare(cv.dragPane, float64(x-cv.lastX), float64(y-cv.lastY))
+		}
+		cv.lastX, cv.lastY = x, y
+	} else if !pressed {
+		cv.dragPane = -1
+	}
+	return true
+}
+
+// drawMapIcon draws the named icon centered on x, y at the size used on the
+// map for zoom. It returns false when the icon is not loaded.
+func (g *Game) drawMapIcon(dst *ebiten.Image, name string, x, y, zoom float64) bool {
+	img := g.icons[name]
+	if img == nil {
+		return false
+	}
+	op := &ebiten.DrawImageOptions{Filter: g.filterMode()}
+	maxDim := math.Max(float64(img.Bounds().Dx()), float64(img.Bounds().Dy()))
+	scale := zoom * IconScale * g.iconScale * float64(BaseIconPixels) / maxDim
+	op.GeoM.Scale(scale, scale)
+	w := float64(img.Bounds().Dx()) * scale
+	h := float64(img.Bounds().Dy()) * scale
+	op.GeoM.Translate(math.Round(x-w/2), math.Round(y-h/2))
+	dst.DrawImage(img, op)
+	return true
+}
+
+// drawComparePane draws one asteroid of the comparison into r.
+func (g *Game) drawComparePane(dst *ebiten.Image, r image.Rectangle, p *comparePane, zoom float64) {
+	pane := dst.SubImage(r).(*ebiten.Image)
+	a, ok := p.asteroid()
+	if !ok {
+		return
+	}
+	ox := float64(r.Min.X) + p.camX
+	oy := float64(r.Min.Y) + p.camY
+	g.drawSpace(pane, a.SizeX, a.SizeY, ox, oy, zoom)
+	for _, bp := range a.BiomePaths.Paths {
+		clr, ok := biomeColors[bp.Name]
+		if !ok {
+			clr = color.RGBA{60, 60, 60, 255}
+		}
+		if tex := g.biomeTextures[bp.Name]; g.textures && tex != nil {
+			drawBiomeTextured(pane, bp.Polygons, tex, clr, ox, oy, zoom, g.filterMode())
+		} else {
+			drawBiome(pane, bp.Polygons, clr, ox, oy, zoom)
+		}
+		drawBiomeOutline(pane, bp.Polygons, ox, oy, zoom, colorWhite)
+	}
+	dot := func(x, y float64) {
+		vector.DrawFilledRect(pane, float32(x-2), float32(y-2), 4, 4, colorWhite, true)
+	}
+	for _, gy := range a.Geysers {
+		x, y := ox+float64(gy.X)*2*zoom, oy+float64(gy.Y)*2*zoom
+		if !g.drawMapIcon(pane, iconForGeyser(gy.ID), x, y, zoom) {
+			dot(x, y)
+		}
+	}
+	for _, poi := range a.POIs {
+		x, y := ox+float64(poi.X)*2*zoom, oy+float64(poi.Y)*2*zoom
+		if !g.drawMapIcon(pane, iconForPOI(poi.ID), x, y, zoom) {
+			dot(x, y)
+		}
+	}
+}
+
+// drawCompareTable draws the comparison table over the panes.
+func (g *Game) drawCompareTable(dst *ebiten.Image, area image.Rectangle) {
+	rows := g.compareRows()
+	if len(rows) == 0 {
+		return
+	}
+	pad := uiScaled(6)
+	var cols [3]int
+	for _, r := range rows {
+		for i, s := range []string{r.Label, r.Left, r.Right} {
+			if w, _ := textDimensions(truncateString(s, CompareCellMaxLen)); w > cols[i] {
+				cols[i] = w
+			}
+		}
+	}
+	w := cols[0] + cols[1] + cols[2] + pad*4
+	h := len(rows)*rowSpacing() + pad*2
+	x := area.Min.X + max(0, (area.Dx()-w)/2)
+	top := area.Min.Y + uiScaled(ClusterMapMargin)
+	drawFrame(dst, image.Rect(x, top, x+w, min(top+h, area.Max.Y)))
+	view := dst.SubImage(area).(*ebiten.Image)
+	y := top + pad - int(g.compare.tableScroll)
+	for _, r := range rows {
+		cx := x + pad
+		for i, s := range []string{r.Label, r.Left, r.Right} {
+			drawText(view, truncateString(s, CompareCellMaxLen), cx, y, false)
+			cx += cols[i] + pad
+		}
+		y += rowSpacing()
+	}
+}
+
+// drawCompareScreen draws the comparison view in place of the map.
+func (g *Game) drawCompareScreen(dst *ebiten.Image) bool {
+	if !g.showCompare || g.compare == nil {
+		return false
+	}
+	cv := g.compare
+	l := g.compareLayout()
+	dst.Fill(backgroundColor)
+	for i := range cv.panes {
+		p := &cv.panes[i]
+		r := l.panes[i]
+		g.drawComparePane(dst, r, p, cv.zoom)
+		label := p.coord
+		if _, ok := p.asteroid(); ok {
+			label += "  " + p.asteroidID
+			if len(p.asteroids) > 1 {
+				label += "  >"
+			}
+		} else if cv.status != "" {
+			drawTextWithBG(dst, cv.status, r.Min.X+r.Dx()/2, r.Min.Y+r.Dy()/2, true)
+		}
+		drawTextWithBG(dst, label, r.Min.X+uiScaled(6), r.Min.Y+uiScaled(4), false)
+	}
+	half := float32(g.width / 2)
+	vector.StrokeLine(dst, half, float32(l.bar.Max.Y), half, float32(g.height), 2, buttonBorderColor, false)
+
+	drawFrame(dst, l.bar)
+	pad := uiScaled(6)
+	lh := menuButtonHeight() - 5
+	if notoFont != nil {
+		lh = notoFont.Metrics().Height.Ceil()
+	}
+	for _, b := range []struct {
+		r      image.Rectangle
+		label  string
+		active bool
+	}{{l.link, CompareLinkLabel, cv.linked}, {l.table, CompareTableLabel, cv.showTable}} {
+		drawButton(dst, b.r, b.active)
+		drawText(dst, b.label, b.r.Min.X+pad, b.r.Min.Y+(b.r.Dy()-lh)/2, false)
+	}
+	drawCloseButton(dst, l.close)
+	if cv.showTable {
+		g.drawCompareTable(dst, image.Rect(0, l.bar.Max.Y, g.width, g.height))
+	}
+	g.needsRedraw = false
+	g.lastDraw = time.Now()
+	return true
+}
@@ -15,7 +15,27 @@ const (
 	IgnoreSeedProtoCertErrors = false
 	AcceptProtoHeader         = "application/protobuf"
 	GzipEncoding              = "gzip"
-	PanSpeed                  = 15
+	// SeedCacheDirName is the directory created inside the user cache
+	// directory to hold downloaded seed protobufs.
+	SeedCacheDirName = "oni-seedview"
+	// ConfigDirName is the directory created inside the user config
+	// directory for saved annotations and settings. The browser build uses
+	// it as the localStorage key prefix.
+	ConfigDirName = "oni-seedview"
+	// AnnotationsFile holds the map annotations of all seeds.
+	AnnotationsFile = "annotations.json"
+	// SettingsFile holds the options menu settings.
+	SettingsFile = "settings.json"
+	// SeedFetchTimeout bounds a single seed download attempt.
+	SeedFetchTimeout = 30 * time.Second
+	// SeedFetchRetries is how many times a failed seed download is retried
+	// after server errors, rate limiting or timeouts.
+	SeedFetchRetries = 3
+	// SeedFetchBaseDelay is the first retry backoff; it doubles per attempt
+	// up to SeedFetchMaxDelay.
+	SeedFetchBaseDelay = 1 * time.Second
+	SeedFetchMaxDelay  = 30 * time.Second
+	PanSpeed           = 15
 	// CameraMargin controls how far the world can be panned
 	// beyond the visible screen in pixels.
 	CameraMargin    = -64
@@ -50,12 +70,106 @@ const (
 	ScreenshotSaveLabel   = "Save Screenshot"
 	ScreenshotTakingLabel = "Taking Screenshot..."
 	ScreenshotSavedLabel  = "Saved!"
+	ScreenshotFailedLabel = "Save failed"
 	ScreenshotBWLabel     = "Black and White"
+	ScreenshotNotesLabel  = "Annotations"
 	ScreenshotCancelLabel = "Cancel"
+	ScreenshotFormatLabel = "Format: "
+	ScreenshotJPEGLabel   = "JPEG quality: "
+	// LoadingBarWidth is the width of the download progress bar.
+	LoadingBarWidth = 300
 	// ScrollBarWidth specifies the width of pseudo scroll bars.
-	ScrollBarWidth    = 6
-	OptionsMenuTitle  = "Options:"
-	AsteroidMenuTitle = "Asteroids:"
+	ScrollBarWidth     = 6
+	OptionsMenuTitle   = "Options:"
+	ResetSettingsLabel = "Reset to Defaults"
+	AsteroidMenuTitle  = "Asteroids:"
+	ChangeSeedLabel    = "Change Seed..."
+	// SearchWorkers is the default number of seeds the search command loads
+	// in parallel.
+	SearchWorkers = 4
+	// SearchRate is the default limit of seed downloads per second for the
+	// search command, to stay polite to the seed server.
+	SearchRate = 2.0
+	// RenderScale is the default number of pixels per world cell used by
+	// the headless renderer.
+	RenderScale = 4.0
+	// RenderJPEGQuality is the default JPEG quality of rendered images.
+	RenderJPEGQuality = 90
+	// SVGLegendGap is the space in world cells between an SVG map and its
+	// legend.
+	SVGLegendGap      = 8.0
+	ClusterMapLabel   = "Cluster Map"
+	ClusterMapTitle   = "Cluster map:"
+	ScoreLabel        = "Seed Score"
+	ScoreTitle        = "Score:"
+	ExportJSONLabel   = "Export JSON"
+	ExportCSVLabel    = "Export CSV"
+	FindLabel         = "Find Items"
+	CompareLabel      = "Compare..."
+	CompareInputTitle = "Compare with seed:"
+	CompareLinkLabel  = "Link Camera"
+	CompareTableLabel = "Table"
+	// CompareCellMaxLen limits the characters shown in a comparison table
+	// cell.
+	CompareCellMaxLen = 48
+	FindAllLabel      = "All Asteroids"
+	AnnotateLabel     = "Annotate Map"
+	AnnotateTitle     = "Annotate:"
+	// AnnotationTextMaxLen limits the length of an annotation's text.
+	AnnotationTextMaxLen = 48
+	ShareLabel           = "Share Link"
+	ShareCopiedLabel     = "Link copied"
+	// ShareStatusDuration is how long the share confirmation stays visible.
+	ShareStatusDuration = 3 * time.Second
+	// ExportStatusDuration is how long the result of an export stays
+	// visible.
+	ExportStatusDuration = 3 * time.Second
+	ExportDoneLabel      = "Exported"
+	// ExportAsteroidJSONLabel and ExportAsteroidCSVLabel export only the
+	// shown asteroid.
+	ExportAsteroidJSONLabel = "Export Asteroid JSON"
+	ExportAsteroidCSVLabel  = "Export Asteroid CSV"
+	// LoadErrorDuration is how long a load error stays over the map that
+	// was kept.
+	LoadErrorDuration = 8 * time.Second
+	// DefaultShareBase is the viewer address desktop share links point at
+	// unless -share-base is given.
+	DefaultShareBase = "view.html"
+	// LinkUpdateInterval is the minimum time between browser address bar
+	// updates while the view changes.
+	LinkUpdateInterval = 500 * time.Millisecond
+	// FindBarWidth is the unscaled width of the search overlay.
+	FindBarWidth = 520
+	// FindQueryMaxLen limits the length of the search text.
+	FindQueryMaxLen = 32
+	// FindMarkerRadius is the unscaled radius of the circles around matches.
+	FindMarkerRadius = 14
+	// GridMinSpacing is the unscaled minimum distance in pixels between
+	// tile grid lines; the grid switches to a coarser spacing below it.
+	GridMinSpacing = 8
+	// CycleSeconds is the length of one in-game cycle.
+	CycleSeconds = 600
+	// ClusterThumbSize is the longest side in pixels of the cached asteroid
+	// thumbnails drawn on the cluster map.
+	ClusterThumbSize = 256
+	// ClusterMapMargin is the unscaled margin around the cluster map.
+	ClusterMapMargin = 20
+	// ClusterMapGap is the spacing in cells between asteroids when a
+	// cluster has no offsets and is laid out as a grid.
+	ClusterMapGap  = 40
+	SeedInputTitle = "Seed coordinate:"
+	// SeedInputWidth is the minimum width of the seed entry overlay.
+	SeedInputWidth = 360
+	// SeedInputMaxLen limits the length of a typed coordinate.
+	SeedInputMaxLen = 64
+	// GeyserQueryMaxLen limits the length of the geyser list search.
+	GeyserQueryMaxLen = 32
+	// FlyToZoom is the minimum zoom after picking a geyser from the list.
+	FlyToZoom = 4.0
+	// FlyDuration is how long the camera takes to fly to a geyser.
+	FlyDuration = 400 * time.Millisecond
+	// RecentSeedLimit is how many previously loaded seeds are remembered.
+	RecentSeedLimit = 10
 
 	// BiomeTextureScale controls the repetition of biome textures.
 	// Smaller values result in more repetitions.
@@ -84,6 +198,10 @@ var ScreenshotQualities = []string{"Low", "Medium", "High"}
 
 var ScreenshotScales = []float64{4.0, 8.0, 10.0}
 
+// ScreenshotJPEGQualities are the JPEG quality levels offered in the
+// screenshot menu.
+var ScreenshotJPEGQualities = []int{75, 90, 95}
+
 var biomeOrder = []string{
 	"Sandstone",
 	"Barren",
@@ -121,4 +239,12 @@ var (
 	backgroundColor     = color.RGBA{30, 30, 30, 255}
 	scrollBarColor      = color.RGBA{0, 128, 255, 255}
 	scrollBarTrackColor = color.RGBA{200, 200, 200, 255}
+	passColor           = color.RGBA{0, 200, 80, 255}
+	podLineColor        = color.RGBA{255, 255, 255, 96}
+	findMatchColor      = color.RGBA{255, 200, 0, 255}
+	annotationColor     = color.RGBA{255, 80, 200, 255}
+	gridMinorColor      = color.RGBA{255, 255, 255, 24}
+	gridMediumColor     = color.RGBA{255, 255, 255, 56}
+	gridMajorColor      = color.RGBA{255, 255, 255, 110}
+	failColor           = color.RGBA{220, 40, 40, 255}
 )
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Coordinate is a parsed world generation coordinate such as
+// "V-FRST-C-1331877-0-0-0". The cluster prefix may itself contain dashes so
+// the last four fields are always seed, settings, story traits and mixing.
+type Coordinate struct {
+	Prefix   string
+	Seed     int
+	Settings string
+	Story    string
+	Mixing   string
+}
+
+// clusterInfo describes a cluster coordinate prefix.
+type clusterInfo struct {
+	Name string
+	// SpacedOut reports whether the cluster requires the Spaced Out DLC.
+	SpacedOut bool
+}
+
+// clusterPrefixes maps coordinate prefixes to cluster names. Names follow
+// the starting asteroid of each cluster.
+var clusterPrefixes = map[string]clusterInfo{
+	// Base game
+	"SNDST-A": {Name: "Terra"},
+	"OCAN-A":  {Name: "Oceania"},
+	"S-FRZ":   {Name: "Rime"},
+	"LUSH-A":  {Name: "Arboria"},
+	"FRST-A":  {Name: "Verdante"},
+	"VOLCA":   {Name: "Volcanea"},
+	"BAD-A":   {Name: "The Badlands"},
+	"HTFST-A": {Name: "Aridio"},
+	"OASIS-A": {Name: "Oasisse"},
+	"CER-A":   {Name: "Ceres"},
+	"CERS-A":  {Name: "Blasted Ceres"},
+	"PRE-A":   {Name: "Relica"},
+	"PRES-A":  {Name: "Shattered Relica"},
+
+	// Spaced Out classic style clusters
+	"V-SNDST-C": {Name: "Terra", SpacedOut: true},
+	"V-OCAN-C":  {Name: "Oceania", SpacedOut: true},
+	"V-SWMP-C":  {Name: "Squelchy", SpacedOut: true},
+	"V-SFRZ-C":  {Name: "Rime", SpacedOut: true},
+	"V-LUSH-C":  {Name: "Arboria", SpacedOut: true},
+	"V-FRST-C":  {Name: "Verdante", SpacedOut: true},
+	"V-VOLCA-C": {Name: "Volcanea", SpacedOut: true},
+	"V-BAD-C":   {Name: "The Badlands", SpacedOut: true},
+	"V-HTFST-C": {Name: "Aridio", SpacedOut: true},
+	"V-OASIS-C": {Name: "Oasisse", SpacedOut: true},
+	"V-CER-C":   {Name: "Ceres", SpacedOut: true},
+	"V-CERS-C":  {Name: "Blasted Ceres", SpacedOut: true},
+	"V-PRE-C":   {Name: "Relica", SpacedOut: true},
+	"V-PRES-C":  {Name: "Shattered Relica", SpacedOut: true},
+
+	// Spaced Out clusters
+	"SNDST-C":  {Name: "Terrania", SpacedOut: true},
+	"CER-C":    {Name: "Ceres Minor", SpacedOut: true},
+	"FRST-C":   {Name: "Folia", SpacedOut: true},
+	"SWMP-C":   {Name: "Quagmiris", SpacedOut: true},
+	"PRE-C":    {Name: "Relica Minor", SpacedOut: true},
+	"M-SWMP-C": {Name: "Metallic Swampy", SpacedOut: true},
+	"M-BAD-C":  {Name: "The Desolands", SpacedOut: true},
+	"M-FRZ-C":  {Name: "Frozen Forest", SpacedOut: true},
+	"M-FLIP-C": {Name: "Flipped", SpacedOut: true},
+	"M-RAD-C":  {Name: "Radioactive Ocean", SpacedOut: true},
+	"M-CERS-C": {Name: "Mini Shattered", SpacedOut: true},
+}
+
+const coordinateFormat = "CLUSTER-SEED-SETTINGS-STORY-MIXING"
+
+// maxSeed is the largest world generation seed.
+const maxSeed = 1<<31 - 1
+
+// ParseCoordinate parses and validates a coordinate string. Input is trimmed
+// and upper-cased. Only the structure is checked, so prefixes missing from
+// clusterPrefixes are accepted. Errors describe the offending field so they
+// can be shown to the user before any network request is made.
+func ParseCoordinate(s string) (Coordinate, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if s == "" {
+		return Coordinate{}, errors.New("enter a seed coordinate")
+	}
+	parts := strings.Split(s, "-")
+	if len(parts) < 5 {
+		return Coordinate{}, fmt.Errorf("coordinate %q has %d fields, expected %s", s, len(parts), coordinateFormat)
+	}
+	n := len(parts)
+	prefix := strings.Join(parts[:n-4], "-")
+	for _, p := range parts[:n-4] {
+		if p == "" {
+			return Coordinate{}, fmt.Errorf("cluster prefix %q has an empty part", prefix)
+		}
+		if !isCoordinateCode(p) {
+			return Coordinate{}, fmt.Errorf("cluster prefix %q may only contain letters, digits and dashes", prefix)
+		}
+	}
+	seedStr := parts[n-4]
+	if seedStr == "" {
+		return Coordinate{}, errors.New("seed is empty")
+	}
+	seed, err := strconv.ParseUint(seedStr, 10, 31)
+	if err != nil {
+		var numErr *strconv.NumError
+		if errors.As(err, &numErr) && errors.Is(numErr.Err, strconv.ErrRange) {
+			return Coordinate{}, fmt.Errorf("seed %s is out of range (maximum %d)", seedStr, maxSeed)
+		}
+		return Coordinate{}, fmt.Errorf("seed %q is not a number", seedStr)
+	}
+	c := Coordinate{
+		Prefix:   prefix,
+		Seed:     int(seed),
+		Settings: parts[n-3],
+		Story:    parts[n-2],
+		Mixing:   parts[n-1],
+	}
+	for _, f := range []struct{ name, val string }{
+		{"settings", c.Settings},
+		{"story traits", c.Story},
+		{"mixing", c.Mixing},
+	} {
+		if err := checkCoordinateCode(f.name, f.val); err != nil {
+			return Coordinate{}, err
+		}
+	}
+	return c, nil
+}
+
+// checkCoordinateCode validates one of the base36 encoded trailing fields.
+func checkCoordinateCode(name, val string) error {
+	if val == "" {
+		return fmt.Errorf("%s field is empty", name)
+	}
+	if !isCoordinateCode(val) {
+		return fmt.Errorf("%s field %q may only contain letters and digits", name, val)
+	}
+	return nil
+}
+
+// isCoordinateCode reports whether val holds only upper-case letters and
+// digits.
+func isCoordinateCode(val string) bool {
+	for _, r := range val {
+		if (r < '0' || r > '9') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the canonical coordinate string.
+func (c Coordinate) String() string {
+	return fmt.Sprintf("%s-%d-%s-%s-%s", c.Prefix, c.Seed, c.Settings, c.Story, c.Mixing)
+}
+
+// KnownCluster reports whether the cluster prefix is one of clusterPrefixes.
+// Other prefixes are still valid, e.g. clusters added by a newer game
+// version.
+func (c Coordinate) KnownCluster() bool {
+	_, ok := clusterPrefixes[c.Prefix]
+	return ok
+}
+
+// ClusterName returns the display name of the coordinate's cluster, or its
+// prefix when the cluster is unknown.
+func (c Coordinate) ClusterName() string {
+	if info, ok := clusterPrefixes[c.Prefix]; ok {
+		return info.Name
+	}
+	return c.Prefix
+}
+
+// SpacedOut reports whether the cluster belongs to the Spaced Out DLC.
+func (c Coordinate) SpacedOut() bool {
+	return clusterPrefixes[c.Prefix].SpacedOut
+}
+
+// Describe returns a short human readable summary such as
+// "Verdante cluster, seed 1331877", or "unknown cluster X-C, seed 7" when
+// the prefix is not one of clusterPrefixes.
+func (c Coordinate) Describe() string {
+	if !c.KnownCluster() {
+		return fmt.Sprintf("unknown cluster %s, seed %d", c.Prefix, c.Seed)
+	}
+	return fmt.Sprintf("%s cluster, seed %d", c.ClusterName(), c.Seed)
+}
+
+// describeCoordinate returns Describe for a valid coordinate string and an
+// empty string otherwise.
+func describeCoordinate(s string) string {
+	c, err := ParseCoordinate(s)
+	if err != nil {
+		return ""
+	}
+	return c.Describe()
+}
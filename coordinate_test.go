@@ -0,0 +1,69 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestParseCoordinateRoundTrip verifies valid coordinates parse and format
+// back to the same string.
+func TestParseCoordinateRoundTrip(t *testing.T) {
+	tests := []struct {
+		in, cluster string
+		seed        int
+	}{
+		{"V-FRST-C-1331877-0-0-0", "Verdante", 1331877},
+		{"SNDST-A-7-0-0-0", "Terra", 7},
+		{"M-FLIP-C-42-0-D3-0", "Flipped", 42},
+		{"PRE-A-2147483647-1A-0-5G", "Relica", 2147483647},
+		// Unknown prefixes are accepted and named after the prefix.
+		{"V-NEW-C-12-0-0-0", "V-NEW-C", 12},
+	}
+	for _, tt := range tests {
+		c, err := ParseCoordinate(strings.ToLower(tt.in))
+		if err != nil {
+			t.Fatalf("ParseCoordinate(%q) error: %v", tt.in, err)
+		}
+		if c.String() != tt.in {
+			t.Errorf("String() = %q, want %q", c.String(), tt.in)
+		}
+		if c.ClusterName() != tt.cluster || c.Seed != tt.seed {
+			t.Errorf("%s: got cluster %q seed %d", tt.in, c.ClusterName(), c.Seed)
+		}
+	}
+	c, _ := ParseCoordinate("V-FRST-C-1331877-0-0-0")
+	if got := c.Describe(); got != "Verdante cluster, seed 1331877" {
+		t.Errorf("Describe() = %q", got)
+	}
+	c, _ = ParseCoordinate("V-NEW-C-12-0-0-0")
+	if got := c.Describe(); got != "unknown cluster V-NEW-C, seed 12" {
+		t.Errorf("Describe() = %q", got)
+	}
+}
+
+// TestParseCoordinateErrors verifies malformed input is rejected with a
+// message naming the bad field.
+func TestParseCoordinateErrors(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", "enter a seed coordinate"},
+		{"SNDST-A-7", "expected CLUSTER-SEED-SETTINGS-STORY-MIXING"},
+		{"FOO_A-7-0-0-0", `cluster prefix "FOO_A" may only contain`},
+		{"SNDST-A-x7-0-0-0", `seed "X7" is not a number`},
+		{"SNDST-A-9999999999-0-0-0", "out of range"},
+		{"SNDST-A-7--0-0", "settings field is empty"},
+		{"SNDST-A-7-0-0-?", `mixing field "?"`},
+		{"-SNDST-A-7-0-0-0", "empty part"},
+	}
+	for _, tt := range tests {
+		_, err := ParseCoordinate(tt.in)
+		if err == nil {
+			t.Errorf("ParseCoordinate(%q) succeeded", tt.in)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ParseCoordinate(%q) error %q, want %q", tt.in, err, tt.want)
+		}
+	}
+}
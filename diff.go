@@ -0,0 +1,354 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// seedDiff describes how a seed changed between two versions of its data.
+// Only asteroids with differences are listed.
+type seedDiff struct {
+	Old              string         `json:"old"`
+	New              string         `json:"new"`
+	AddedAsteroids   []string       `json:"addedAsteroids,omitempty"`
+	RemovedAsteroids []string       `json:"removedAsteroids,omitempty"`
+	Asteroids        []asteroidDiff `json:"asteroids,omitempty"`
+}
+
+// asteroidDiff holds the changes of one asteroid present in both versions.
+type asteroidDiff struct {
+	ID            string      `json:"id"`
+	OldSize       string      `json:"oldSize,omitempty"`
+	NewSize       string      `json:"newSize,omitempty"`
+	AddedTraits   []string    `json:"addedTraits,omitempty"`
+	RemovedTraits []string    `json:"removedTraits,omitempty"`
+	Geysers       itemDiff    `json:"geysers"`
+	POIs          itemDiff    `json:"pois"`
+	Biomes        []biomeDiff `json:"biomes,omitempty"`
+}
+
+// itemDiff lists the geysers or POIs that were added, removed or moved, and
+// the geysers whose emission stats changed.
+type itemDiff struct {
+	Added   []itemRef      `json:"added,omitempty"`
+	Removed []itemRef      `json:"removed,omitempty"`
+	Moved   []itemMove     `json:"moved,omitempty"`
+	Changed []geyserChange `json:"changed,omitempty"`
+}
+
+type itemRef struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	X    int    `json:"x"`
+	Y    int    `json:"y"`
+}
+
+type itemMove struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	FromX int    `json:"fromX"`
+	FromY int    `json:"fromY"`
+	ToX   int    `json:"toX"`
+	ToY   int    `json:"toY"`
+}
+
+// geyserChange lists the stats that differ for a geyser found in both
+// versions, at its new position.
+type geyserChange struct {
+	itemRef
+	Stats []statChange `json:"stats"`
+}
+
+type statChange struct {
+	Stat string  `json:"stat"`
+	Old  float64 `json:"old"`
+	New  float64 `json:"new"`
+}
+
+// biomeDiff describes a biome whose outline changed. Area is in cells.
+type biomeDiff struct {
+	Name       string  `json:"name"`
+	Change     string  `json:"change"`
+	OldRegions int     `json:"oldRegions"`
+	NewRegions int     `json:"newRegions"`
+	OldArea    float64 `json:"oldArea"`
+	NewArea    float64 `json:"newArea"`
+}
+
+// diffStats are the geyser stats compared by diffSeeds, named as in
+// geyserStats.
+var diffStats = []string{"emitRate", "avgEmitRate", "eruptionTime", "idleTime", "activeCycles", "dormancyCycles"}
+
+func (d itemDiff) empty() bool {
+	return len(d.Added)+len(d.Removed)+len(d.Moved)+len(d.Changed) == 0
+}
+
+func (d asteroidDiff) empty() bool {
+	return d.OldSize == "" && len(d.AddedTraits)+len(d.RemovedTraits)+len(d.Biomes) == 0 &&
+		d.Geysers.empty() && d.POIs.empty()
+}
+
+// empty reports whether the two versions are equivalent.
+func (d seedDiff) empty() bool {
+	return len(d.AddedAsteroids)+len(d.RemovedAsteroids)+len(d.Asteroids) == 0
+}
+
+// diffSeeds compares two versions of a seed. Asteroids are matched by ID.
+// Geysers and POIs are matched by type, first at the same position and then
+// by nearest distance, which reports the pair as moved.
+func diffSeeds(oldCoord string, old *SeedData, newCoord string, cur *SeedData) seedDiff {
+	d := seedDiff{Old: oldCoord, New: newCoord}
+	for _, a := range cur.Asteroids {
+		idx := asteroidIndexByID(old.Asteroids, a.ID)
+		if idx < 0 {
+			d.AddedAsteroids = append(d.AddedAsteroids, a.ID)
+			continue
+		}
+		if ad := diffAsteroids(old.Asteroids[idx], a); !ad.empty() {
+			d.Asteroids = append(d.Asteroids, ad)
+		}
+	}
+	for _, a := range old.Asteroids {
+		if asteroidIndexByID(cur.Asteroids, a.ID) < 0 {
+			d.RemovedAsteroids = append(d.RemovedAsteroids, a.ID)
+		}
+	}
+	return d
+}
+
+func diffAsteroids(old, cur Asteroid) asteroidDiff {
+	d := asteroidDiff{ID: cur.ID}
+	if old.SizeX != cur.SizeX || old.SizeY != cur.SizeY {
+		d.OldSize = fmt.Sprintf("%dx%d", old.SizeX, old.SizeY)
+		d.NewSize = fmt.Sprintf("%dx%d", cur.SizeX, cur.SizeY)
+	}
+	d.AddedTraits = missingFrom(cur.Traits, old.Traits)
+	d.RemovedTraits = missingFrom(old.Traits, cur.Traits)
+
+	oldGeysers := make([]diffItem, len(old.Geysers))
+	for i, g := range old.Geysers {
+		oldGeysers[i] = diffItem{g.ID, g.X, g.Y, g}
+	}
+	curGeysers := make([]diffItem, len(cur.Geysers))
+	for i, g := range cur.Geysers {
+		curGeysers[i] = diffItem{g.ID, g.X, g.Y, g}
+	}
+	d.Geysers = diffItems(oldGeysers, curGeysers, exportGeyserName)
+	oldPOIs := make([]diffItem, len(old.POIs))
+	for i, p := range old.POIs {
+		oldPOIs[i] = diffItem{ID: p.ID, X: p.X, Y: p.Y}
+	}
+	curPOIs := make([]diffItem, len(cur.POIs))
+	for i, p := range cur.POIs {
+		curPOIs[i] = diffItem{ID: p.ID, X: p.X, Y: p.Y}
+	}
+	d.POIs = diffItems(oldPOIs, curPOIs, exportPOIName)
+	d.Biomes = diffBiomes(old.BiomePaths.Paths, cur.BiomePaths.Paths)
+	return d
+}
+
+// missingFrom returns the entries of a not in b.
+func missingFrom(a, b []string) []string {
+	var out []string
+	for _, s := range a {
+		found := false
+		for _, t := range b {
+			if s == t {
+				found = true
+				break
+			}
+		}
+		if !found {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// diffItem is a geyser or POI being matched. geyser is zero for POIs.
+type diffItem struct {
+	ID     string
+	X, Y   int
+	geyser Geyser
+}
+
+func diffItems(old, cur []diffItem, name func(string) string) itemDiff {
+	var d itemDiff
+	ref := func(it diffItem) itemRef {
+		return itemRef{simplifyID(it.ID), name(it.ID), it.X, it.Y}
+	}
+	usedOld := make([]bool, len(old))
+	usedCur := make([]bool, len(cur))
+	var pairs [][2]int
+	// Items of the same type at the same position are unchanged or have
+	// changed stats.
+	for j, c := range cur {
+		for i, o := range old {
+			if !usedOld[i] && o.ID == c.ID && o.X == c.X && o.Y == c.Y {
+				usedOld[i], usedCur[j] = true, true
+				pairs = append(pairs, [2]int{i, j})
+				break
+			}
+		}
+	}
+	// Pair the remaining items of each type by nearest distance.
+	type candidate struct {
+		i, j int
+		dist float64
+	}
+	var cands []candidate
+	for i, o := range old {
+		for j, c := range cur {
+			if !usedOld[i] && !usedCur[j] && o.ID == c.ID {
+				cands = append(cands, candidate{i, j, tileDistance(Point{o.X, o.Y}, Point{c.X, c.Y})})
+			}
+		}
+	}
+	sort.SliceStable(cands, func(a, b int) bool { return cands[a].dist < cands[b].dist })
+	for _, c := range cands {
+		if usedOld[c.i] || usedCur[c.j] {
+			continue
+		}
+		usedOld[c.i], usedCur[c.j] = true, true
+		o, n := old[c.i], cur[c.j]
+		d.Moved = append(d.Moved, itemMove{simplifyID(n.ID), name(n.ID), o.X, o.Y, n.X, n.Y})
+		pairs = append(pairs, [2]int{c.i, c.j})
+	}
+	for _, p := range pairs {
+		if stats := diffGeyserStats(old[p[0]].geyser, cur[p[1]].geyser); len(stats) > 0 {
+			d.Changed = append(d.Changed, geyserChange{ref(cur[p[1]]), stats})
+		}
+	}
+	for j, c := range cur {
+		if !usedCur[j] {
+			d.Added = append(d.Added, ref(c))
+		}
+	}
+	for i, o := range old {
+		if !usedOld[i] {
+			d.Removed = append(d.Removed, ref(o))
+		}
+	}
+	return d
+}
+
+func diffGeyserStats(old, cur Geyser) []statChange {
+	var out []statChange
+	for _, s := range diffStats {
+		a, b := geyserStats[s](old), geyserStats[s](cur)
+		if math.Abs(a-b) > 1e-6 {
+			out = append(out, statChange{s, a, b})
+		}
+	}
+	return out
+}
+
+// biomeRegions groups the polygons of paths by biome name.
+func biomeRegions(paths []BiomePath) map[string][][]Point {
+	out := make(map[string][][]Point)
+	for _, bp := range paths {
+		out[bp.Name] = append(out[bp.Name], bp.Polygons...)
+	}
+	return out
+}
+
+// polygonArea returns the combined area in cells of polys.
+func polygonArea(polys [][]Point) float64 {
+	total := 0.0
+	for _, poly := range polys {
+		a := 0
+		for i := range poly {
+			p, q := poly[i], poly[(i+1)%len(poly)]
+			a += p.X*q.Y - q.X*p.Y
+		}
+		total += math.Abs(float64(a)) / 2
+	}
+	return total
+}
+
+func diffBiomes(old, cur []BiomePath) []biomeDiff {
+	o, c := biomeRegions(old), biomeRegions(cur)
+	names := make(map[string]bool)
+	for n := range o {
+		names[n] = true
+	}
+	for n := range c {
+		names[n] = true
+	}
+	var out []biomeDiff
+	for n := range names {
+		op, inOld := o[n]
+		cp, inCur := c[n]
+		if inOld && inCur && reflect.DeepEqual(op, cp) {
+			continue
+		}
+		change := "changed"
+		switch {
+		case !inOld:
+			change = "added"
+		case !inCur:
+			change = "removed"
+		}
+		out = append(out, biomeDiff{displayBiome(n), change, len(op), len(cp), polygonArea(op), polygonArea(cp)})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// writeDiffText writes d in a human-readable form.
+func writeDiffText(w io.Writer, d seedDiff) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", d.Old, d.New)
+	if d.empty() {
+		b.WriteString("no differences\n")
+	}
+	for _, id := range d.AddedAsteroids {
+		fmt.Fprintf(&b, "asteroid added: %s\n", id)
+	}
+	for _, id := range d.RemovedAsteroids {
+		fmt.Fprintf(&b, "asteroid removed: %s\n", id)
+	}
+	for _, a := range d.Asteroids {
+		fmt.Fprintf(&b, "%s:\n", a.ID)
+		if a.OldSize != "" {
+			fmt.Fprintf(&b, "  size %s -> %s\n", a.OldSize, a.NewSize)
+		}
+		for _, t := range a.AddedTraits {
+			fmt.Fprintf(&b, "  trait added: %s\n", worldTraitName(t))
+		}
+		for _, t := range a.RemovedTraits {
+			fmt.Fprintf(&b, "  trait removed: %s\n", worldTraitName(t))
+		}
+		writeItemDiff(&b, "geyser", a.Geysers)
+		writeItemDiff(&b, "poi", a.POIs)
+		for _, bd := range a.Biomes {
+			fmt.Fprintf(&b, "  biome %s: %s, %d -> %d regions, %s -> %s cells\n", bd.Change, bd.Name,
+				bd.OldRegions, bd.NewRegions, formatNum(bd.OldArea), formatNum(bd.NewArea))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeItemDiff(b *strings.Builder, kind string, d itemDiff) {
+	for _, it := range d.Added {
+		fmt.Fprintf(b, "  %s added: %s at %d,%d\n", kind, it.Name, it.X, it.Y)
+	}
+	for _, it := range d.Removed {
+		fmt.Fprintf(b, "  %s removed: %s at %d,%d\n", kind, it.Name, it.X, it.Y)
+	}
+	for _, m := range d.Moved {
+		fmt.Fprintf(b, "  %s moved: %s %d,%d -> %d,%d\n", kind, m.Name, m.FromX, m.FromY, m.ToX, m.ToY)
+	}
+	for _, c := range d.Changed {
+		parts := make([]string, len(c.Stats))
+		for i, s := range c.Stats {
+			parts[i] = fmt.Sprintf("%s %s -> %s", s.Stat, formatNum(s.Old), formatNum(s.New))
+		}
+		fmt.Fprintf(b, "  %s changed: %s at %d,%d: %s\n", kind, c.Name, c.X, c.Y, strings.Join(parts, ", "))
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+)
+
+// runDiff implements the "diff" subcommand which reports what changed
+// between two versions of a seed. Like diff(1) it exits with 0 when the
+// versions match, 1 when they differ and 2 on errors.
+func runDiff(args []string) int {
+	fs := newCommandFlags("diff", "diff [flags] OLD NEW | COORD\n\n"+
+		"OLD and NEW are coordinates or FILE.pb[.gz] files. With a single COORD the\n"+
+		"cached copy is compared with a fresh download, which then replaces it.\n")
+	src := addSeedSourceFlags(fs)
+	asJSON := fs.Bool("json", false, "print the differences as JSON")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	src.apply()
+	if fs.NArg() < 1 || fs.NArg() > 2 {
+		fs.Usage()
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	var d seedDiff
+	var err error
+	if fs.NArg() == 1 {
+		d, err = diffCachedSeed(ctx, parseSeedSource(fs.Arg(0)).coord)
+	} else {
+		d, err = diffSeedSources(ctx, fs.Arg(0), fs.Arg(1))
+	}
+	if err != nil {
+		commandError(os.Stderr, "diff", err)
+		return 2
+	}
+	if *asJSON {
+		err = writeDiffJSON(os.Stdout, d)
+	} else {
+		err = writeDiffText(os.Stdout, d)
+	}
+	if err != nil {
+		commandError(os.Stderr, "diff", err)
+		return 2
+	}
+	if d.empty() {
+		return 0
+	}
+	return 1
+}
+
+// diffSeedSources loads the seeds named by oldArg and newArg and compares
+// them.
+func diffSeedSources(ctx context.Context, oldArg, newArg string) (seedDiff, error) {
+	old, err := loadSeed(ctx, parseSeedSource(oldArg))
+	if err != nil {
+		return seedDiff{}, fmt.Errorf("%s: %v", oldArg, err)
+	}
+	cur, err := loadSeed(ctx, parseSeedSource(newArg))
+	if err != nil {
+		return seedDiff{}, fmt.Errorf("%s: %v", newArg, err)
+	}
+	return diffSeeds(oldArg, old, newArg, cur), nil
+}
+
+// diffCachedSeed compares the cached copy of coord with the current server
+// data and stores the download in the cache.
+func diffCachedSeed(ctx context.Context, coord string) (seedDiff, error) {
+	if offlineMode {
+		return seedDiff{}, fmt.Errorf("offline mode: comparing %s with the server needs network access", coord)
+	}
+	entry, data, err := seedProtoCache.get(coord)
+	if err != nil {
+		return seedDiff{}, fmt.Errorf("%s: no cached copy to compare with", coord)
+	}
+	old, err := decodeSeedProto(data)
+	if err != nil {
+		return seedDiff{}, fmt.Errorf("%s: cached copy: %v", coord, err)
+	}
+	body, etag, err := fetchSeedProtoContext(ctx, coord, "", nil)
+	if err != nil {
+		return seedDiff{}, fmt.Errorf("%s: %v", coord, err)
+	}
+	cur, err := decodeSeedProto(body)
+	if err != nil {
+		return seedDiff{}, fmt.Errorf("%s: %v", coord, err)
+	}
+	_ = seedProtoCache.put(coord, body, etag)
+	oldName := fmt.Sprintf("%s (cached %s)", coord, entry.Fetched.Format("2006-01-02"))
+	return diffSeeds(oldName, old, coord+" (server)", cur), nil
+}
+
+func writeDiffJSON(w io.Writer, d seedDiff) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(d)
+}
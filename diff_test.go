@@ -0,0 +1,71 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffSeeds(t *testing.T) {
+	steam := Geyser{ID: "steam", X: 10, Y: 20, EmitRate: 3000, EruptionTime: 150, IdleTime: 450}
+	square := func(n int) [][]Point { return [][]Point{{{0, 0}, {n, 0}, {n, n}, {0, n}}} }
+	old := &SeedData{Asteroids: []Asteroid{
+		{ID: "A", SizeX: 240, SizeY: 380, Traits: []string{"traits/MetalPoor"},
+			Geysers: []Geyser{steam, {ID: "molten_gold", X: 50, Y: 60}},
+			POIs:    []PointOfInterest{{ID: "Headquarters", X: 100, Y: 100}},
+			BiomePaths: BiomePathsCompact{Paths: []BiomePath{
+				{Name: "Sandstone", Polygons: square(10)},
+				{Name: "Swamp", Polygons: square(5)},
+			}}},
+		{ID: "B"},
+	}}
+	changedSteam := steam
+	changedSteam.EmitRate = 3100
+	cur := &SeedData{Asteroids: []Asteroid{
+		{ID: "A", SizeX: 240, SizeY: 380, Traits: []string{"traits/MetalPoor"},
+			Geysers: []Geyser{changedSteam, {ID: "molten_gold", X: 55, Y: 60}, {ID: "methane", X: 1, Y: 2}},
+			POIs:    []PointOfInterest{{ID: "Headquarters", X: 100, Y: 100}},
+			BiomePaths: BiomePathsCompact{Paths: []BiomePath{
+				{Name: "Sandstone", Polygons: square(12)},
+				{Name: "Swamp", Polygons: square(5)},
+			}}},
+		{ID: "C"},
+	}}
+
+	d := diffSeeds("old", old, "new", cur)
+	if len(d.AddedAsteroids) != 1 || d.AddedAsteroids[0] != "C" || len(d.RemovedAsteroids) != 1 || d.RemovedAsteroids[0] != "B" {
+		t.Fatalf("asteroids added %v removed %v", d.AddedAsteroids, d.RemovedAsteroids)
+	}
+	if len(d.Asteroids) != 1 {
+		t.Fatalf("got %d changed asteroids, want 1", len(d.Asteroids))
+	}
+	a := d.Asteroids[0]
+	if a.OldSize != "" || a.AddedTraits != nil || a.RemovedTraits != nil || !a.POIs.empty() {
+		t.Errorf("unexpected changes: %+v", a)
+	}
+	g := a.Geysers
+	if len(g.Added) != 1 || g.Added[0].ID != "methane" || len(g.Removed) != 0 {
+		t.Errorf("geysers added %+v removed %+v", g.Added, g.Removed)
+	}
+	if len(g.Moved) != 1 || g.Moved[0].FromX != 50 || g.Moved[0].ToX != 55 {
+		t.Errorf("geysers moved %+v", g.Moved)
+	}
+	if len(g.Changed) != 1 || len(g.Changed[0].Stats) != 1 || g.Changed[0].Stats[0] != (statChange{"emitRate", 3000, 3100}) {
+		t.Errorf("geysers changed %+v", g.Changed)
+	}
+	if len(a.Biomes) != 1 || a.Biomes[0].Change != "changed" || a.Biomes[0].OldArea != 100 || a.Biomes[0].NewArea != 144 {
+		t.Errorf("biomes %+v", a.Biomes)
+	}
+
+	var b strings.Builder
+	if err := writeDiffText(&b, d); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"asteroid added: C", "asteroid removed: B", "geyser moved: ", "emitRate 3000 -> 3100"} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("text output missing %q:\n%s", want, b.String())
+		}
+	}
+	if d := diffSeeds("a", old, "b", old); !d.empty() {
+		t.Errorf("identical seeds differ: %+v", d)
+	}
+}
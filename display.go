@@ -178,14 +178,22 @@ func formatNum(n float64) string {
 }
 
 func formatGeyserInfo(g Geyser) string {
-	return fmt.Sprintf("POS: %d,%d\nActive Cycles: %s\nAvg Emit Rate: %s\nDormancy Cycles: %s\nEmit Rate: %s\nEruption Time: %s\nIdle Time: %s",
+	info := fmt.Sprintf("POS: %d,%d\nEffective Output: %s g/s\nPer Cycle: %s kg\nActive Cycles: %s\nAvg Emit Rate: %s\nDormancy Cycles: %s\nEmit Rate: %s\nEruption Time: %s\nIdle Time: %s",
 		g.X, g.Y,
+		formatNum(g.effectiveOutput()),
+		formatNum(g.effectiveOutputPerCycle()),
 		formatNum(g.ActiveCycles),
 		formatNum(g.AvgEmitRate),
 		formatNum(g.DormancyCycles),
 		formatNum(g.EmitRate),
 		formatNum(g.EruptionTime),
 		formatNum(g.IdleTime))
+	if t, ok := lookupGeyserType(g.ID); ok {
+		info += fmt.Sprintf("\nQuality: %s\nOutput: %s at %s C\nRange: %s-%s kg per active cycle",
+			geyserQualityLabel(g), t.Element, formatNum(t.Temperature-273.15),
+			formatNum(t.MinRate), formatNum(t.MaxRate))
+	}
+	return info
 }
 
 func formatPOIInfo(p PointOfInterest) string {
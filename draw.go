@@ -17,39 +17,21 @@ func (g *Game) Draw(screen *ebiten.Image) {
 	if g.drawLoadingScreen(screen) {
 		return
 	}
+	if g.drawScoreScreen(screen) {
+		return
+	}
+	if g.drawClusterMapScreen(screen) {
+		if g.showSeedInput {
+			g.drawSeedInput(screen)
+		}
+		return
+	}
+	if g.drawCompareScreen(screen) {
+		return
+	}
 	if g.needsRedraw {
 		screen.Fill(backgroundColor)
-		if g.textures && g.biomeTextures != nil {
-			if tex := g.biomeTextures["Space"]; tex != nil {
-				clr := colorWhite
-				if !g.noColor {
-					if c, ok := biomeColors["Space"]; ok {
-						clr = c
-					}
-				}
-				rect := [][]Point{{
-					{0, 0},
-					{g.astWidth, 0},
-					{g.astWidth, g.astHeight},
-					{0, g.astHeight},
-				}}
-				drawBiomeTextured(screen, rect, tex, clr, g.camX, g.camY, g.zoom, g.filterMode())
-			} else if clr, ok := biomeColors["Space"]; ok {
-				if g.noColor {
-					clr = colorWhite
-				}
-				vector.DrawFilledRect(screen, float32(g.camX), float32(g.camY),
-					float32(float64(g.astWidth)*2*g.zoom),
-					float32(float64(g.astHeight)*2*g.zoom), clr, false)
-			}
-		} else if clr, ok := biomeColors["Space"]; ok {
-			if g.noColor {
-				clr = colorWhite
-			}
-			vector.DrawFilledRect(screen, float32(g.camX), float32(g.camY),
-				float32(float64(g.astWidth)*2*g.zoom),
-				float32(float64(g.astHeight)*2*g.zoom), clr, false)
-		}
+		g.drawSpace(screen, g.astWidth, g.astHeight, g.camX, g.camY, g.zoom)
 		labels := []label{}
 		var highlightGeysers []Geyser
 		var highlightPOIs []PointOfInterest
@@ -85,6 +67,7 @@ func (g *Game) Draw(screen *ebiten.Image) {
 			outlineClr := colorWhite
 			drawBiomeOutline(screen, bp.Polygons, g.camX, g.camY, g.zoom, outlineClr)
 		}
+		g.drawGrid(screen)
 		for _, gy := range g.geysers {
 			x := math.Round((float64(gy.X) * 2 * g.zoom) + g.camX)
 			y := math.Round((float64(gy.Y) * 2 * g.zoom) + g.camY)
@@ -211,7 +194,7 @@ func (g *Game) Draw(screen *ebiten.Image) {
 		}
 
 		if g.coord != "" && !g.screenshotMode && g.showItemNames {
-			label := g.coord
+			label := g.seedLabel()
 			aName := g.asteroidID
 			if aName == "" {
 				aName = "Unknown"
@@ -228,6 +211,9 @@ func (g *Game) Draw(screen *ebiten.Image) {
 			ar := g.asteroidArrowRect()
 			drawDownArrow(screen, ar, g.showAstMenu)
 			drawText(screen, label, x, seedBaseline(), true)
+			if traits := g.asteroidTraitsLabel(); traits != "" {
+				drawText(screen, traits, x, traitsBaseline(), true)
+			}
 		}
 
 		if g.showLegend && !g.noColor {
@@ -327,6 +313,7 @@ func (g *Game) Draw(screen *ebiten.Image) {
 			}
 		}
 
+		g.drawPodLines(screen, highlightGeysers)
 		highlightLabels := []label{}
 		for _, gy := range highlightGeysers {
 			x := math.Round((float64(gy.X) * 2 * g.zoom) + g.camX)
@@ -428,8 +415,50 @@ func (g *Game) Draw(screen *ebiten.Image) {
 			}
 		}
 
+		g.drawAnnotations(screen)
+		g.drawFindMarkers(screen)
 		g.drawUI(screen)
+		g.drawFindBar(screen)
+		g.drawAnnotateBar(screen)
+		g.drawShareStatus(screen)
+		g.drawExportStatus(screen)
+		g.drawLoadError(screen)
 	}
-	g.captureScreen(screen)
 
 }
+
+// drawSpace fills the w by h cell area of an asteroid with the space
+// background.
+func (g *Game) drawSpace(dst *ebiten.Image, w, h int, camX, camY, zoom float64) {
+	if g.textures && g.biomeTextures != nil {
+		if tex := g.biomeTextures["Space"]; tex != nil {
+			clr := colorWhite
+			if !g.noColor {
+				if c, ok := biomeColors["Space"]; ok {
+					clr = c
+				}
+			}
+			rect := [][]Point{{
+				{0, 0},
+				{w, 0},
+				{w, h},
+				{0, h},
+			}}
+			drawBiomeTextured(dst, rect, tex, clr, camX, camY, zoom, g.filterMode())
+		} else if clr, ok := biomeColors["Space"]; ok {
+			if g.noColor {
+				clr = colorWhite
+			}
+			vector.DrawFilledRect(dst, float32(camX), float32(camY),
+				float32(float64(w)*2*zoom),
+				float32(float64(h)*2*zoom), clr, false)
+		}
+	} else if clr, ok := biomeColors["Space"]; ok {
+		if g.noColor {
+			clr = colorWhite
+		}
+		vector.DrawFilledRect(dst, float32(camX), float32(camY),
+			float32(float64(w)*2*zoom),
+			float32(float64(h)*2*zoom), clr, false)
+	}
+}
@@ -1,15 +1,9 @@
 package main
 
 import (
-	"fmt"
-	"image"
 	"image/color"
-	"math"
-	"os"
 	"time"
 
-	"golang.org/x/image/bmp"
-
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/vector"
 )
@@ -24,12 +18,9 @@ func (g *Game) drawUI(screen *ebiten.Image) {
 		vector.StrokeLine(screen, float32(cx-size), float32(cy), float32(cx+size), float32(cy), float32(thickness), crossClr, true)
 		vector.StrokeLine(screen, float32(cx), float32(cy-size), float32(cx), float32(cy+size), float32(thickness), crossClr, true)
 		if g.showItemNames {
-			worldX := int(math.Round(((float64(cx) - g.camX) / g.zoom) / 2))
-			worldY := int(math.Round(((float64(cy) - g.camY) / g.zoom) / 2))
-			coords := fmt.Sprintf("X: %d Y: %d", worldX, worldY)
 			lh := notoFont.Metrics().Height.Ceil()
 			y := g.height - lh - uiScaled(8)
-			drawTextWithBGScale(screen, coords, uiScaled(5), y, 1, false)
+			drawTextWithBGScale(screen, g.statusText(), uiScaled(5), y, 1, false)
 		}
 	}
 
@@ -66,22 +57,10 @@ func (g *Game) drawUI(screen *ebiten.Image) {
 		cr := g.helpCloseRect()
 		drawCloseButton(screen, cr)
 	}
+	if g.showSeedInput && !g.screenshotMode {
+		g.drawSeedInput(screen)
+	}
 
 	g.needsRedraw = false
 	g.lastDraw = time.Now()
 }
-
-func (g *Game) captureScreen(screen *ebiten.Image) {
-	if g.screenshotPath == "" || g.captured {
-		return
-	}
-	b := screen.Bounds()
-	pixels := make([]byte, 4*b.Dx()*b.Dy())
-	screen.ReadPixels(pixels)
-	img := &image.RGBA{Pix: pixels, Stride: 4 * b.Dx(), Rect: b}
-	if f, err := os.Create(g.screenshotPath); err == nil {
-		_ = bmp.Encode(f, img)
-		_ = f.Close()
-	}
-	g.captured = true
-}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// seedExport is the JSON document written by the export command and the
+// in-app export button.
+type seedExport struct {
+	Coordinate string           `json:"coordinate"`
+	Cluster    string           `json:"cluster,omitempty"`
+	Software   string           `json:"software"`
+	Asteroids  []asteroidExport `json:"asteroids"`
+}
+
+type asteroidExport struct {
+	ID      string         `json:"id"`
+	Width   int            `json:"width"`
+	Height  int            `json:"height"`
+	OffsetX int            `json:"offsetX"`
+	OffsetY int            `json:"offsetY"`
+	Traits  []namedExport  `json:"traits"`
+	Biomes  []namedExport  `json:"biomes"`
+	Geysers []geyserExport `json:"geysers"`
+	POIs    []poiExport    `json:"pois"`
+}
+
+// namedExport pairs a game ID with its display name.
+type namedExport struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type geyserExport struct {
+	ID             string  `json:"id"`
+	Name           string  `json:"name"`
+	X              int     `json:"x"`
+	Y              int     `json:"y"`
+	EmitRate       float64 `json:"emitRate"`
+	AvgEmitRate    float64 `json:"avgEmitRate"`
+	EruptionTime   float64 `json:"eruptionTime"`
+	IdleTime       float64 `json:"idleTime"`
+	ActiveCycles   float64 `json:"activeCycles"`
+	DormancyCycles float64 `json:"dormancyCycles"`
+	// EffectiveOutput is the long-term average in g/s and
+	// OutputPerCycle the same in kg per cycle.
+	EffectiveOutput float64 `json:"effectiveOutput"`
+	OutputPerCycle  float64 `json:"outputPerCycle"`
+}
+
+type poiExport struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	X    int    `json:"x"`
+	Y    int    `json:"y"`
+}
+
+// exportGeyserName returns the full display name of a geyser. Exports use
+// the long names rather than the abbreviated ones drawn on the map.
+func exportGeyserName(id string) string {
+	if v, ok := names.Geysers[simplifyID(id)]; ok {
+		return v
+	}
+	return simplifyID(id)
+}
+
+func exportPOIName(id string) string {
+	if v, ok := names.POIs[simplifyID(id)]; ok {
+		return v
+	}
+	return simplifyID(id)
+}
+
+// buildSeedExport converts asts, which belong to the seed at coord, into
+// the export document.
+func buildSeedExport(coord string, asts []Asteroid) seedExport {
+	out := seedExport{
+		Coordinate: coord,
+		Software:   "Oni-SeedView " + ClientVersion,
+		Asteroids:  make([]asteroidExport, 0, len(asts)),
+	}
+	if c, err := ParseCoordinate(coord); err == nil {
+		out.Cluster = c.ClusterName()
+	}
+	for _, a := range asts {
+		ae := asteroidExport{
+			ID:      a.ID,
+			Width:   a.SizeX,
+			Height:  a.SizeY,
+			OffsetX: a.OffsetX,
+			OffsetY: a.OffsetY,
+			Traits:  []namedExport{},
+			Biomes:  []namedExport{},
+			Geysers: make([]geyserExport, 0, len(a.Geysers)),
+			POIs:    make([]poiExport, 0, len(a.POIs)),
+		}
+		for _, t := range a.Traits {
+			ae.Traits = append(ae.Traits, namedExport{ID: t, Name: worldTraitName(t)})
+		}
+		seen := make(map[string]bool)
+		for _, bp := range a.BiomePaths.Paths {
+			if seen[bp.Name] {
+				continue
+			}
+			seen[bp.Name] = true
+			ae.Biomes = append(ae.Biomes, namedExport{ID: bp.Name, Name: displayBiome(bp.Name)})
+		}
+		for _, gy := range a.Geysers {
+			ae.Geysers = append(ae.Geysers, geyserExport{
+				ID:              gy.ID,
+				Name:            exportGeyserName(gy.ID),
+				X:               gy.X,
+				Y:               gy.Y,
+				EmitRate:        gy.EmitRate,
+				AvgEmitRate:     gy.AvgEmitRate,
+				EruptionTime:    gy.EruptionTime,
+				IdleTime:        gy.IdleTime,
+				ActiveCycles:    gy.ActiveCycles,
+				DormancyCycles:  gy.DormancyCycles,
+				EffectiveOutput: gy.effectiveOutput(),
+				OutputPerCycle:  gy.effectiveOutputPerCycle(),
+			})
+		}
+		for _, p := range a.POIs {
+			ae.POIs = append(ae.POIs, poiExport{ID: p.ID, Name: exportPOIName(p.ID), X: p.X, Y: p.Y})
+		}
+		out.Asteroids = append(out.Asteroids, ae)
+	}
+	return out
+}
+
+// writeSeedJSON writes asts as an indented JSON document.
+func writeSeedJSON(w io.Writer, coord string, asts []Asteroid) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(buildSeedExport(coord, asts))
+}
+
+var geyserCSVHeader = []string{
+	"coordinate", "asteroid", "id", "name", "x", "y",
+	"emit_rate", "avg_emit_rate", "eruption_time", "idle_time",
+	"active_cycles", "dormancy_cycles", "effective_output", "output_per_cycle",
+}
+
+// writeGeyserCSV writes one row per geyser of asts with every emission stat.
+func writeGeyserCSV(w io.Writer, coord string, asts []Asteroid) error {
+	cw := csv.NewWriter(w)
+	cw.Write(geyserCSVHeader)
+	for _, a := range asts {
+		for _, gy := range a.Geysers {
+			cw.Write([]string{
+				coord, a.ID, gy.ID, exportGeyserName(gy.ID),
+				strconv.Itoa(gy.X), strconv.Itoa(gy.Y),
+				formatCSVFloat(gy.EmitRate), formatCSVFloat(gy.AvgEmitRate),
+				formatCSVFloat(gy.EruptionTime), formatCSVFloat(gy.IdleTime),
+				formatCSVFloat(gy.ActiveCycles), formatCSVFloat(gy.DormancyCycles),
+				formatCSVFloat(gy.effectiveOutput()), formatCSVFloat(gy.effectiveOutputPerCycle()),
+			})
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+var poiCSVHeader = []string{"coordinate", "asteroid", "id", "name", "x", "y"}
+
+// writePOICSV writes one row per point of interest of asts.
+func writePOICSV(w io.Writer, coord string, asts []Asteroid) error {
+	cw := csv.NewWriter(w)
+	cw.Write(poiCSVHeader)
+	for _, a := range asts {
+		for _, p := range a.POIs {
+			cw.Write([]string{
+				coord, a.ID, p.ID, exportPOIName(p.ID),
+				strconv.Itoa(p.X), strconv.Itoa(p.Y),
+			})
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatCSVFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// exportFile is one encoded export and the suffix appended to its base name.
+type exportFile struct {
+	suffix string
+	data   []byte
+}
+
+// exportFiles encodes asts as a JSON document, or as separate geyser and POI
+// CSV tables.
+func exportFiles(coord string, asts []Asteroid, format string) []exportFile {
+	var files []exportFile
+	encode := func(suffix string, write func(io.Writer, string, []Asteroid) error) {
+		var buf bytes.Buffer
+		// Writes to a bytes.Buffer cannot fail.
+		_ = write(&buf, coord, asts)
+		files = append(files, exportFile{suffix, buf.Bytes()})
+	}
+	if format == "csv" {
+		encode("-geysers.csv", writeGeyserCSV)
+		encode("-pois.csv", writePOICSV)
+	} else {
+		encode(".json", writeSeedJSON)
+	}
+	return files
+}
+
+// exportSeedData saves the loaded seed, or only the shown asteroid when
+// current is set, in format ("json" or "csv") through the same download path
+// as screenshots. The result is shown below the asteroid name.
+func (g *Game) exportSeedData(format string, current bool) {
+	asts, id := g.asteroids, ""
+	if current {
+		i := asteroidIndexByID(g.asteroids, g.asteroidID)
+		if i < 0 {
+			return
+		}
+		asts, id = g.asteroids[i:i+1], g.asteroidID
+	}
+	if len(asts) == 0 {
+		return
+	}
+	base := exportFileName(g.coord, id)
+	g.exportStatus = ExportDoneLabel
+	for _, f := range exportFiles(g.coord, asts, format) {
+		if err := saveImageData(base+f.suffix, f.data); err != nil {
+			g.exportStatus = "Export failed: " + err.Error()
+			break
+		}
+	}
+	g.exportTime = time.Now()
+	g.needsRedraw = true
+}
+
+// drawExportStatus shows the result of the last export below the asteroid
+// name, under the share status if both are shown, for ExportStatusDuration.
+func (g *Game) drawExportStatus(dst *ebiten.Image) {
+	if g.exportStatus == "" {
+		return
+	}
+	if time.Since(g.exportTime) >= ExportStatusDuration {
+		g.exportStatus = ""
+		return
+	}
+	r := g.asteroidInfoRect()
+	y := r.Max.Y + uiScaled(6)
+	if g.shareStatus != "" {
+		y += menuSpacing()
+	}
+	drawTextWithBG(dst, g.exportStatus, r.Min.X+r.Dx()/2, y, true)
+}
+
+// exportFileName builds the base name for exported files, such as
+// "V-FRST-C-1331877-0-0-0" or "V-FRST-C-1331877-0-0-0-Vanilla_Verdante".
+// asteroidID may be empty when the whole seed is exported.
+func exportFileName(coord, asteroidID string) string {
+	if asteroidID == "" {
+		return sanitizeFileName(coord)
+	}
+	return sanitizeFileName(coord + "-" + asteroidID)
+}
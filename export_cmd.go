@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+)
+
+// runExport implements the "export" subcommand which writes decoded seed data
+// as JSON or CSV.
+func runExport(args []string) int {
+	fs := newCommandFlags("export", "export [flags] COORD|FILE.pb[.gz]...")
+	src := addSeedSourceFlags(fs)
+	asteroids := fs.String("asteroid", "all", "comma separated asteroid IDs to export, or \"all\"")
+	format := fs.String("format", "json", "output format: json, or csv for geyser and POI tables")
+	outDir := fs.String("out", ".", "directory for exported files")
+	output := fs.String("o", "", "output file for a single JSON export, or - for stdout")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	src.apply()
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+	*format = strings.ToLower(*format)
+	if *format != "json" && *format != "csv" {
+		commandError(os.Stderr, "export", fmt.Errorf("unknown format %q (use json or csv)", *format))
+		return 2
+	}
+	if *output != "" && (*format != "json" || fs.NArg() > 1) {
+		commandError(os.Stderr, "export", fmt.Errorf("-o can only be used for a single JSON export; use -out for batches"))
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ids := splitList(*asteroids)
+	failed := false
+	for _, arg := range fs.Args() {
+		if ctx.Err() != nil {
+			return 1
+		}
+		paths, err := exportSeed(ctx, parseSeedSource(arg), ids, *format, *outDir, *output)
+		for _, p := range paths {
+			if p != "-" {
+				fmt.Println(p)
+			}
+		}
+		if err != nil {
+			commandError(os.Stderr, "export", fmt.Errorf("%s: %v", arg, err))
+			failed = true
+		}
+	}
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+// exportSeed writes the selected asteroids of one seed and returns the paths
+// written.
+func exportSeed(ctx context.Context, src seedSource, ids []string, format, outDir, output string) ([]string, error) {
+	seed, err := loadSeed(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+	asts, err := selectAsteroids(seed, ids)
+	if err != nil {
+		return nil, err
+	}
+	asteroidID := ""
+	if len(asts) == 1 && len(seed.Asteroids) > 1 {
+		asteroidID = asts[0].ID
+	}
+	base := filepath.Join(outDir, exportFileName(src.coord, asteroidID))
+	files := exportFiles(src.coord, asts, format)
+	var paths []string
+	for _, f := range files {
+		path := base + f.suffix
+		if output != "" {
+			path = output
+		}
+		if err := writeExportFile(path, f.data); err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// writeExportFile writes data to path, creating parent directories. A path
+// of "-" writes to stdout.
+func writeExportFile(path string, data []byte) error {
+	if path == "-" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+)
+
+// TestSeedExport verifies display names and emission stats survive the JSON
+// and CSV exports.
+func TestSeedExport(t *testing.T) {
+	asts := []Asteroid{{
+		ID:      "Terra",
+		SizeX:   20,
+		SizeY:   10,
+		OffsetX: 3,
+		Traits:  []string{"GeoActive"},
+		Geysers: []Geyser{{ID: "steam", X: 5, Y: 6, EmitRate: 5000, EruptionTime: 300, IdleTime: 400}},
+		POIs:    []PointOfInterest{{ID: "Headquarters", X: 10, Y: 4}},
+	}}
+
+	var buf bytes.Buffer
+	if err := writeSeedJSON(&buf, "SNDST-A-7-0-0-0", asts); err != nil {
+		t.Fatal(err)
+	}
+	var doc seedExport
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Cluster != "Terra" || len(doc.Asteroids) != 1 {
+		t.Fatalf("unexpected document %+v", doc)
+	}
+	a := doc.Asteroids[0]
+	if a.OffsetX != 3 || len(a.Traits) != 1 || a.Traits[0].Name != worldTraitName("GeoActive") {
+		t.Fatalf("unexpected asteroid %+v", a)
+	}
+	if gy := a.Geysers[0]; gy.Name != names.Geysers["steam"] || gy.EmitRate != 5000 || gy.IdleTime != 400 {
+		t.Fatalf("unexpected geyser %+v", gy)
+	}
+	if p := a.POIs[0]; p.Name != names.POIs["Headquarters"] || p.X != 10 {
+		t.Fatalf("unexpected POI %+v", p)
+	}
+
+	buf.Reset()
+	if err := writeGeyserCSV(&buf, "SNDST-A-7-0-0-0", asts); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || len(rows[1]) != len(geyserCSVHeader) {
+		t.Fatalf("unexpected geyser rows %q", rows)
+	}
+	if rows[1][1] != "Terra" || rows[1][4] != "5" || rows[1][6] != "5000" {
+		t.Fatalf("unexpected geyser row %q", rows[1])
+	}
+}
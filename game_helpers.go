@@ -11,48 +11,88 @@ import (
 )
 
 type Game struct {
-	geysers           []Geyser
-	pois              []PointOfInterest
-	biomes            []BiomePath
-	asteroids         []Asteroid
-	icons             map[string]*ebiten.Image
-	biomeTextures     map[string]*ebiten.Image
-	width             int
-	height            int
-	astWidth          int
-	astHeight         int
-	camX              float64
-	camY              float64
-	zoom              float64
-	minZoom           float64
-	dragging          bool
-	lastX             int
-	lastY             int
-	touches           map[ebiten.TouchID]touchPoint
-	pinchDist         float64
-	needsRedraw       bool
-	screenshotPath    string
-	captured          bool
-	legend            *ebiten.Image
-	legendMap         map[string]int
-	legendEntries     []string
-	legendColors      []color.RGBA
-	legendImage       *ebiten.Image
-	legendBiomes      []string
-	geyserItems       []geyserListItem
-	hoverBiome        int
-	hoverItem         int
-	selectedBiome     int
-	selectedItem      int
-	showGeyserList    bool
-	geyserScroll      float64
+	geysers        []Geyser
+	pois           []PointOfInterest
+	biomes         []BiomePath
+	asteroids      []Asteroid
+	icons          map[string]*ebiten.Image
+	biomeTextures  map[string]*ebiten.Image
+	width          int
+	height         int
+	astWidth       int
+	astHeight      int
+	camX           float64
+	camY           float64
+	zoom           float64
+	minZoom        float64
+	dragging       bool
+	lastX          int
+	lastY          int
+	touches        map[ebiten.TouchID]touchPoint
+	pinchDist      float64
+	needsRedraw    bool
+	legend         *ebiten.Image
+	legendMap      map[string]int
+	legendEntries  []string
+	legendColors   []color.RGBA
+	legendImage    *ebiten.Image
+	legendBiomes   []string
+	geyserItems    []geyserListItem
+	hoverBiome     int
+	hoverItem      int
+	selectedBiome  int
+	selectedItem   int
+	showGeyserList bool
+	geyserScroll   float64
+	geyserSort     int
+	geyserFilter   int
+	geyserQuery    string
+	flight         *cameraFlight
+	showFind       bool
+	findQuery      string
+	findAll        bool
+	findMatches    []findMatch
+	findIndex      int
+	annotations    annotationStore
+	annotating     bool
+	annotTool      int
+	annotEditing   bool
+	annotEditKey   string
+	annotEdit      int
+	annotText      string
+	annotPress     *image.Point
+	annotLast      image.Point
+	annotMoved     bool
+	annotDrag      *annotation
+	annotErr       string
+	// annotLoadErr is set when the saved annotations exist but could not
+	// be loaded; they are then never saved over.
+	annotLoadErr   error
+	cursorTile     image.Point
+	cursorBiome    string
+	showCompare    bool
+	compare        *compareView
+	compareInput   bool
+	pendingCompare string
+	// pendingView is the camera and item of the link opened at startup,
+	// applied once its asteroid is shown.
+	pendingView       *viewState
+	pageLink          string
+	linkUpdated       time.Time
+	shareStatus       string
+	shareTime         time.Time
+	exportStatus      string
+	exportTime        time.Time
 	biomeScroll       float64
 	itemScroll        float64
 	showHelp          bool
 	lastWheel         time.Time
 	loading           bool
+	load              *seedLoad
+	loadFraction      float64
 	status            string
 	coord             string
+	seedFile          string
 	mobile            bool
 	showInfo          bool
 	infoPinned        bool
@@ -72,31 +112,59 @@ type Game struct {
 	showShotMenu      bool
 	showAstMenu       bool
 	showOptions       bool
+	showSeedInput     bool
+	showClusterMap    bool
+	showScore         bool
+	scoreReport       *scoreReport
+	scoreErr          string
+	scoreScroll       float64
+	clusterThumbs     map[string]*ebiten.Image
+	seedInput         string
+	seedInputErr      string
+	recentSeeds       []string
 	asteroidScroll    float64
 	screenshotMode    bool
 	ssQuality         int
 	ssSaved           time.Time
+	ssErr             string
 	ssPending         int
+	ssFormat          imageFormat
+	ssJPEGQuality     int
 	skipClickTicks    int
 	lastDraw          time.Time
 	wasMinimized      bool
 	asteroidID        string
 	asteroidSpecified bool
 	statusError       bool
+	statusTime        time.Time
 	fitOnLoad         bool
 
 	textures      bool
 	vsync         bool
 	showItemNames bool
 	showLegend    bool
-	useNumbers    bool
-	iconScale     float64
-	smartRender   bool
-	linearFilter  bool
-	hidpi         bool
+	showPodLines  bool
+	// showAnnotations draws the user's pins, notes and shapes.
+	showAnnotations bool
+	showGrid        bool
+	// flipY shows Y coordinates counted from the bottom of the asteroid.
+	flipY        bool
+	useNumbers   bool
+	iconScale    float64
+	smartRender  bool
+	linearFilter bool
+	hidpi        bool
+	// savedSettings are the settings last loaded or saved, see
+	// persistSettings.
+	savedSettings settings
+	// linkOverrides holds the layers the startup deep link switched, by name,
+	// with the value the link gave them. They are not saved, see
+	// withoutLinkLayers.
+	linkOverrides map[string]bool
 
-	noColor   bool
-	ssNoColor bool
+	noColor       bool
+	ssNoColor     bool
+	ssAnnotations bool
 
 	lastHelpClick     time.Time
 	lastShotClick     time.Time
@@ -118,10 +186,11 @@ type touchPoint struct {
 }
 
 type geyserListItem struct {
-	text string
-	icon *ebiten.Image
-	w    int
-	h    int
+	geyser Geyser
+	text   string
+	icon   *ebiten.Image
+	w      int
+	h      int
 }
 
 func (g *Game) iconSize() int { return uiScaled(HelpIconSize) }
@@ -286,7 +355,7 @@ func (g *Game) itemAt(mx, my int) (string, int, int, *ebiten.Image, bool) {
 			}
 		}
 		if float64(mx) >= left && float64(mx) <= right && float64(my) >= top && float64(my) <= bottom {
-			info := displayGeyser(gy.ID) + "\n" + formatGeyserInfo(gy)
+			info := g.geyserInfo(gy)
 			var icon *ebiten.Image
 			if n := iconForGeyser(gy.ID); n != "" {
 				icon = g.icons[n]
@@ -335,6 +404,16 @@ func (g *Game) closeMenus() {
 	g.showOptions = false
 	g.showGeyserList = false
 	g.showHelp = false
+	g.showSeedInput = false
+	g.showClusterMap = false
+	g.showScore = false
+	g.showFind = false
+	if g.annotating {
+		g.closeAnnotate()
+	}
+	if g.compare != nil {
+		g.closeCompare()
+	}
 	g.noColor = false
 }
 
@@ -0,0 +1,325 @@
+package main
+
+import (
+	"image"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Sort orders of the geyser list.
+const (
+	geyserSortType = iota
+	geyserSortRate
+	geyserSortDistance
+	geyserSortPosition
+)
+
+var geyserSortLabels = []string{"Type", "Avg Emit Rate", "Distance", "Position"}
+
+// geyserCategories are the type filters of the geyser list. "All" disables
+// the filter.
+var geyserCategories = []string{"All", "Water", "Gas", "Volcano", "Metal", "Other"}
+
+// geyserCategory returns the filter category of a geyser ID.
+func geyserCategory(id string) string {
+	id = simplifyID(id)
+	switch id {
+	case "steam", "hot_steam", "hot_water", "slush_water", "filthy_water", "slush_salt_water", "salt_water":
+		return "Water"
+	case "hot_co2", "hot_hydrogen", "hot_po2", "slimy_po2", "chlorine_gas", "chlorine_gas_cool", "methane":
+		return "Gas"
+	case "small_volcano", "big_volcano":
+		return "Volcano"
+	}
+	if strings.HasPrefix(id, "molten_") {
+		return "Metal"
+	}
+	return "Other"
+}
+
+// matchesGeyserQuery reports whether every word of query appears in the
+// geyser's name, ID or output element.
+func matchesGeyserQuery(gy Geyser, query string) bool {
+	hay := strings.ToLower(displayGeyser(gy.ID) + " " + simplifyID(gy.ID))
+	if t, ok := lookupGeyserType(gy.ID); ok {
+		hay += " " + strings.ToLower(t.Element)
+	}
+	for _, w := range strings.Fields(strings.ToLower(query)) {
+		if !strings.Contains(hay, w) {
+			return false
+		}
+	}
+	return true
+}
+
+// filterGeysers returns the geysers of category (or all for "All" or "")
+// matching query, ordered by mode. Distances are measured from pod; without
+// a pod the distance order falls back to position.
+func filterGeysers(geysers []Geyser, category, query string, mode int, pod Point, hasPod bool) []Geyser {
+	out := make([]Geyser, 0, len(geysers))
+	for _, gy := range geysers {
+		if category != "" && category != "All" && geyserCategory(gy.ID) != category {
+			continue
+		}
+		if matchesGeyserQuery(gy, query) {
+			out = append(out, gy)
+		}
+	}
+	byPosition := func(a, b Geyser) bool {
+		if a.Y != b.Y {
+			return a.Y < b.Y
+		}
+		return a.X < b.X
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		switch mode {
+		case geyserSortType:
+			if na, nb := displayGeyser(a.ID), displayGeyser(b.ID); na != nb {
+				return na < nb
+			}
+		case geyserSortRate:
+			if a.AvgEmitRate != b.AvgEmitRate {
+				return a.AvgEmitRate > b.AvgEmitRate
+			}
+		case geyserSortDistance:
+			if hasPod {
+				da, db := tileDistance(pod, Point{a.X, a.Y}), tileDistance(pod, Point{b.X, b.Y})
+				if da != db {
+					return da < db
+				}
+			}
+		}
+		return byPosition(a, b)
+	})
+	return out
+}
+
+// visibleGeysers returns the geysers shown in the list with the current sort
+// order and filters.
+func (g *Game) visibleGeysers() []Geyser {
+	pod, ok := findPrintingPod(g.pois)
+	return filterGeysers(g.geysers, geyserCategories[g.geyserFilter], g.geyserQuery, g.geyserSort, pod, ok)
+}
+
+// refreshGeyserList rebuilds the list after the sort order or a filter
+// changed.
+func (g *Game) refreshGeyserList() {
+	g.geyserItems = nil
+	g.geyserScroll = 0
+	g.needsRedraw = true
+}
+
+// geyserListHeader holds the screen rectangles of the controls above the
+// geyser list.
+type geyserListHeader struct {
+	sort       image.Rectangle
+	search     image.Rectangle
+	categories []image.Rectangle
+	height     int
+}
+
+func (g *Game) geyserListHeader() geyserListHeader {
+	var h geyserListHeader
+	spacing := uiScaled(geyserRowSpace)
+	pad := uiScaled(6)
+	bh := menuButtonHeight()
+	sortW := 0
+	for _, l := range geyserSortLabels {
+		if w, _ := textDimensions("Sort: " + l); w > sortW {
+			sortW = w
+		}
+	}
+	x, y := spacing, uiScaled(HelpMargin)
+	h.sort = image.Rect(x, y, x+sortW+pad*2, y+bh)
+	right := max(g.geyserCloseRect().Min.X-pad, h.sort.Max.X+pad+uiScaled(60))
+	h.search = image.Rect(h.sort.Max.X+pad, y, right, y+bh)
+	y += bh + pad
+	for _, c := range geyserCategories {
+		w, _ := textDimensions(c)
+		w += pad * 2
+		if x > spacing && x+w > g.width-spacing {
+			x = spacing
+			y += bh + pad
+		}
+		h.categories = append(h.categories, image.Rect(x, y, x+w, y+bh))
+		x += w + pad
+	}
+	h.height = y + bh + pad
+	return h
+}
+
+// geyserListLayout returns the rectangle of each list item in content
+// coordinates, before scrolling, and the height of the content.
+func (g *Game) geyserListLayout() ([]image.Rectangle, int) {
+	spacing := uiScaled(geyserRowSpace)
+	g.buildGeyserItems()
+	maxW := 0
+	for _, it := range g.geyserItems {
+		if it.w > maxW {
+			maxW = it.w
+		}
+	}
+	cols := 1
+	if maxW+spacing > 0 {
+		cols = max(1, g.width/(maxW+spacing))
+	}
+	rects := make([]image.Rectangle, len(g.geyserItems))
+	y := g.geyserListHeader().height
+	for start := 0; start < len(g.geyserItems); start += cols {
+		end := min(start+cols, len(g.geyserItems))
+		rowH := 0
+		for _, it := range g.geyserItems[start:end] {
+			rowH = max(rowH, it.h)
+		}
+		x := spacing
+		for i := start; i < end; i++ {
+			rects[i] = image.Rect(x, y, x+g.geyserItems[i].w, y+rowH)
+			x += maxW + spacing
+		}
+		y += rowH + spacing
+	}
+	return rects, y
+}
+
+// clickGeyserList handles a click or tap at pt on the list controls or items.
+func (g *Game) clickGeyserList(pt image.Point) {
+	h := g.geyserListHeader()
+	switch {
+	case pt.In(h.sort):
+		g.geyserSort = (g.geyserSort + 1) % len(geyserSortLabels)
+		g.refreshGeyserList()
+		return
+	case pt.In(h.search):
+		if g.geyserQuery != "" {
+			g.geyserQuery = ""
+			g.refreshGeyserList()
+		}
+		return
+	case pt.Y < h.height:
+		for i, r := range h.categories {
+			if pt.In(r) {
+				g.geyserFilter = i
+				g.refreshGeyserList()
+			}
+		}
+		return
+	}
+	rects, _ := g.geyserListLayout()
+	for i, r := range rects {
+		if pt.In(r.Add(image.Pt(0, -int(g.geyserScroll)))) {
+			g.showGeyserList = false
+			g.flyToGeyser(g.geyserItems[i].geyser)
+			return
+		}
+	}
+}
+
+// typeGeyserQuery applies typed characters and Backspace to the search box.
+func (g *Game) typeGeyserQuery() {
+	q := g.geyserQuery
+	for _, r := range ebiten.AppendInputChars(nil) {
+		if utf8.RuneCountInString(q) < GeyserQueryMaxLen {
+			q += string(r)
+		}
+	}
+	if keyRepeat(ebiten.KeyBackspace) && q != "" {
+		_, size := utf8.DecodeLastRuneInString(q)
+		q = q[:len(q)-size]
+	}
+	if q != g.geyserQuery {
+		g.geyserQuery = q
+		g.refreshGeyserList()
+	}
+}
+
+// drawGeyserListHeader draws the sort button, search box and category
+// filters above the list.
+func (g *Game) drawGeyserListHeader(dst *ebiten.Image) {
+	h := g.geyserListHeader()
+	drawFrame(dst, image.Rect(0, 0, g.width, h.height))
+	pad := uiScaled(6)
+	lh := menuButtonHeight() - 5
+	if notoFont != nil {
+		lh = notoFont.Metrics().Height.Ceil()
+	}
+	textY := func(r image.Rectangle) int { return r.Min.Y + (r.Dy()-lh)/2 }
+	drawButton(dst, h.sort, true)
+	drawText(dst, "Sort: "+geyserSortLabels[g.geyserSort], h.sort.Min.X+pad, textY(h.sort), false)
+	drawButton(dst, h.search, false)
+	search := "Search: " + g.geyserQuery + "_"
+	if g.geyserQuery == "" {
+		search = "Type to search"
+	}
+	drawText(dst, search, h.search.Min.X+pad, textY(h.search), false)
+	for i, r := range h.categories {
+		drawButton(dst, r, i == g.geyserFilter)
+		drawText(dst, geyserCategories[i], r.Min.X+pad, textY(r), false)
+	}
+}
+
+// cameraFlight animates the camera from one view to another.
+type cameraFlight struct {
+	fromX, fromY, fromZoom float64
+	toX, toY, toZoom       float64
+	start                  time.Time
+	// pin shows the details of the item at the destination when the flight
+	// ends.
+	pin bool
+}
+
+// viewCenter returns the world position at the center of the screen.
+func (g *Game) viewCenter() (float64, float64) {
+	return (float64(g.width)/2 - g.camX) / (2 * g.zoom), (float64(g.height)/2 - g.camY) / (2 * g.zoom)
+}
+
+// flyTo moves the camera to the world position x, y, zooming in to at least
+// FlyToZoom.
+func (g *Game) flyTo(x, y float64, pin bool) {
+	cx, cy := g.viewCenter()
+	g.flight = &cameraFlight{
+		fromX: cx, fromY: cy, fromZoom: g.zoom,
+		toX: x, toY: y, toZoom: math.Min(math.Max(g.zoom, FlyToZoom), MaxZoom),
+		start: time.Now(),
+		pin:   pin,
+	}
+	g.needsRedraw = true
+}
+
+// flyToGeyser moves the camera to gy and pins its details once there.
+func (g *Game) flyToGeyser(gy Geyser) {
+	g.flyTo(float64(gy.X), float64(gy.Y), true)
+}
+
+// updateFlight advances the camera flight, if any.
+func (g *Game) updateFlight() {
+	f := g.flight
+	if f == nil {
+		return
+	}
+	t := math.Min(float64(time.Since(f.start))/float64(FlyDuration), 1)
+	e := t * t * (3 - 2*t)
+	x := f.fromX + (f.toX-f.fromX)*e
+	y := f.fromY + (f.toY-f.fromY)*e
+	g.zoom = f.fromZoom + (f.toZoom-f.fromZoom)*e
+	g.camX = float64(g.width)/2 - x*2*g.zoom
+	g.camY = float64(g.height)/2 - y*2*g.zoom
+	if t >= 1 {
+		g.flight = nil
+		g.clampCamera()
+		if f.pin {
+			if info, _, _, icon, found := g.itemAt(g.width/2, g.height/2); found {
+				g.infoText = info
+				g.infoIcon = icon
+				g.showInfo = true
+				g.infoPinned = true
+			}
+		}
+	}
+	g.needsRedraw = true
+}
@@ -0,0 +1,47 @@
+package main
+
+import "testing"
+
+func TestFilterGeysers(t *testing.T) {
+	geysers := []Geyser{
+		{ID: "steam", X: 10, Y: 10, AvgEmitRate: 500},
+		{ID: "molten_iron", X: 50, Y: 5, AvgEmitRate: 120},
+		{ID: "hot_water", X: 90, Y: 90, AvgEmitRate: 2000},
+		{ID: "methane", X: 40, Y: 60, AvgEmitRate: 80},
+	}
+	pod := Point{50, 50}
+	ids := func(gs []Geyser) []string {
+		var out []string
+		for _, g := range gs {
+			out = append(out, simplifyID(g.ID))
+		}
+		return out
+	}
+	tests := []struct {
+		name            string
+		category, query string
+		mode            int
+		want            []string
+	}{
+		{"rate", "All", "", geyserSortRate, []string{"hot_water", "steam", "molten_iron", "methane"}},
+		{"distance", "", "", geyserSortDistance, []string{"methane", "molten_iron", "steam", "hot_water"}},
+		{"position", "All", "", geyserSortPosition, []string{"molten_iron", "steam", "methane", "hot_water"}},
+		{"water category", "Water", "", geyserSortPosition, []string{"steam", "hot_water"}},
+		{"metal category", "Metal", "", geyserSortType, []string{"molten_iron"}},
+		{"search by element", "All", "natural gas", geyserSortType, []string{"methane"}},
+		{"no match", "Volcano", "", geyserSortType, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ids(filterGeysers(geysers, tt.category, tt.query, tt.mode, pod, true))
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("got %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+// Geyser emission rates are in grams per second while erupting. A geyser
+// alternates between eruption and idle periods, measured in seconds, while
+// it is active, and between active and dormant periods, measured in cycles.
+
+// eruptionRatio is the share of an active period spent erupting.
+func (g Geyser) eruptionRatio() float64 {
+	return activeShare(g.EruptionTime, g.IdleTime)
+}
+
+// activeRatio is the share of the geyser's life spent active rather than
+// dormant.
+func (g Geyser) activeRatio() float64 {
+	return activeShare(g.ActiveCycles, g.DormancyCycles)
+}
+
+func activeShare(on, off float64) float64 {
+	if on <= 0 {
+		return 0
+	}
+	if off <= 0 {
+		return 1
+	}
+	return on / (on + off)
+}
+
+// effectiveOutput returns the long-term average output in grams per second,
+// accounting for both the eruption/idle and the active/dormant cycles.
+func (g Geyser) effectiveOutput() float64 {
+	return g.EmitRate * g.eruptionRatio() * g.activeRatio()
+}
+
+// effectiveOutputPerCycle returns the long-term average output in kilograms
+// per cycle.
+func (g Geyser) effectiveOutputPerCycle() float64 {
+	return g.effectiveOutput() * CycleSeconds / 1000
+}
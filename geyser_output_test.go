@@ -0,0 +1,34 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGeyserEffectiveOutput(t *testing.T) {
+	tests := []struct {
+		name     string
+		g        Geyser
+		gps, kgc float64
+	}{
+		// Cool Steam Vent erupting a quarter of the time and active 60%.
+		{"steam", Geyser{ID: "steam", EmitRate: 3000, EruptionTime: 150, IdleTime: 450, ActiveCycles: 60, DormancyCycles: 40}, 450, 270},
+		// Water Geyser with an unusually long eruption.
+		{"hot_water", Geyser{ID: "hot_water", EmitRate: 10000, EruptionTime: 540, IdleTime: 60, ActiveCycles: 50, DormancyCycles: 50}, 4500, 2700},
+		// Volcanoes never erupting or never active produce nothing.
+		{"no eruption", Geyser{ID: "big_volcano", EmitRate: 250000, IdleTime: 600, ActiveCycles: 50, DormancyCycles: 50}, 0, 0},
+		{"no activity", Geyser{ID: "big_volcano", EmitRate: 250000, EruptionTime: 60, IdleTime: 600, DormancyCycles: 50}, 0, 0},
+		// Missing idle or dormancy data means the geyser is always on.
+		{"always on", Geyser{ID: "oil_drip", EmitRate: 250, EruptionTime: 600, ActiveCycles: 100}, 250, 150},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.g.effectiveOutput(); math.Abs(got-tt.gps) > 1e-9 {
+				t.Errorf("effectiveOutput = %v, want %v", got, tt.gps)
+			}
+			if got := tt.g.effectiveOutputPerCycle(); math.Abs(got-tt.kgc) > 1e-9 {
+				t.Errorf("effectiveOutputPerCycle = %v, want %v", got, tt.kgc)
+			}
+		})
+	}
+}
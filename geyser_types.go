@@ -0,0 +1,188 @@
+package main
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// geyserTypeInfo holds the ranges a geyser type can roll when a world is
+// generated, taken from the game's geyser configuration.
+type geyserTypeInfo struct {
+	Element string
+	// Temperature is the output temperature in Kelvin.
+	Temperature float64
+	// MinRate and MaxRate bound the mass emitted per active cycle in kg.
+	MinRate, MaxRate float64
+	// MinIteration and MaxIteration bound the length in seconds of one
+	// eruption plus idle period, and MinEruption/MaxEruption the share of it
+	// spent erupting.
+	MinIteration, MaxIteration float64
+	MinEruption, MaxEruption   float64
+	// MinYear and MaxYear bound the length in seconds of one active plus
+	// dormant period, and MinActive/MaxActive the share of it spent active.
+	MinYear, MaxYear     float64
+	MinActive, MaxActive float64
+}
+
+// Defaults shared by most geyser types.
+const (
+	geyserMinIteration = 60
+	geyserMaxIteration = 1140
+	geyserMinEruption  = 0.1
+	geyserMaxEruption  = 0.9
+	geyserMinYear      = 15000
+	geyserMaxYear      = 135000
+	geyserMinActive    = 0.4
+	geyserMaxActive    = 0.8
+)
+
+func geyserType(element string, temp, minRate, maxRate float64) geyserTypeInfo {
+	return geyserTypeInfo{
+		Element:      element,
+		Temperature:  temp,
+		MinRate:      minRate,
+		MaxRate:      maxRate,
+		MinIteration: geyserMinIteration,
+		MaxIteration: geyserMaxIteration,
+		MinEruption:  geyserMinEruption,
+		MaxEruption:  geyserMaxEruption,
+		MinYear:      geyserMinYear,
+		MaxYear:      geyserMaxYear,
+		MinActive:    geyserMinActive,
+		MaxActive:    geyserMaxActive,
+	}
+}
+
+// volcano returns a type with the long, short-burst eruptions of magma
+// volcanoes.
+func volcano(element string, temp, minRate, maxRate float64) geyserTypeInfo {
+	t := geyserType(element, temp, minRate, maxRate)
+	t.MinIteration, t.MaxIteration = 6000, 12000
+	t.MinEruption, t.MaxEruption = 0.005, 0.01
+	return t
+}
+
+// metalVolcano returns a type with the eruption pattern of metal volcanoes.
+func metalVolcano(element string, temp, minRate, maxRate float64) geyserTypeInfo {
+	t := geyserType(element, temp, minRate, maxRate)
+	t.MinIteration, t.MaxIteration = 480, 1080
+	t.MinEruption, t.MaxEruption = 1.0/60, 0.1
+	return t
+}
+
+// geyserTypes is keyed by the IDs returned by geyserTypeFromID.
+var geyserTypes = map[string]geyserTypeInfo{
+	"steam":             geyserType("Steam", 383.15, 1000, 2000),
+	"hot_steam":         geyserType("Steam", 773.15, 500, 1000),
+	"hot_water":         geyserType("Water", 368.15, 2000, 4000),
+	"slush_water":       geyserType("Polluted Water", 263.15, 1000, 2000),
+	"filthy_water":      geyserType("Polluted Water", 303.15, 2000, 4000),
+	"slush_salt_water":  geyserType("Brine", 263.15, 1000, 2000),
+	"salt_water":        geyserType("Salt Water", 368.15, 2000, 4000),
+	"liquid_co2":        geyserType("Liquid Carbon Dioxide", 218, 100, 200),
+	"liquid_sulfur":     geyserType("Liquid Sulfur", 438.35, 1000, 2000),
+	"hot_co2":           geyserType("Carbon Dioxide", 773.15, 70, 140),
+	"hot_hydrogen":      geyserType("Hydrogen", 773.15, 70, 140),
+	"hot_po2":           geyserType("Polluted Oxygen", 773.15, 70, 140),
+	"slimy_po2":         geyserType("Polluted Oxygen", 333.15, 70, 140),
+	"chlorine_gas":      geyserType("Chlorine", 333.15, 70, 140),
+	"chlorine_gas_cool": geyserType("Chlorine", 278.15, 70, 140),
+	"methane":           geyserType("Natural Gas", 423.15, 70, 140),
+	"small_volcano":     volcano("Magma", 2000, 400, 800),
+	"big_volcano":       volcano("Magma", 2000, 800, 1600),
+	"molten_copper":     metalVolcano("Molten Copper", 2500, 200, 400),
+	"molten_iron":       metalVolcano("Molten Iron", 2800, 200, 400),
+	"molten_gold":       metalVolcano("Molten Gold", 2900, 200, 400),
+	"molten_aluminum":   metalVolcano("Molten Aluminum", 2000, 200, 400),
+	"molten_cobalt":     metalVolcano("Molten Cobalt", 2500, 200, 400),
+	"molten_tungsten":   metalVolcano("Molten Tungsten", 4000, 200, 400),
+	"molten_niobium":    volcano("Molten Niobium", 3500, 800, 1600),
+	"oil_drip": {
+		Element:      "Crude Oil",
+		Temperature:  600,
+		MinRate:      1,
+		MaxRate:      250,
+		MinIteration: 600,
+		MaxIteration: 600,
+		MinEruption:  1,
+		MaxEruption:  1,
+		MinYear:      100,
+		MaxYear:      500,
+		MinActive:    geyserMinActive,
+		MaxActive:    geyserMaxActive,
+	},
+}
+
+// lookupGeyserType returns the reference ranges for a geyser ID.
+func lookupGeyserType(id string) (geyserTypeInfo, bool) {
+	t, ok := geyserTypes[simplifyID(id)]
+	return t, ok
+}
+
+// The game maps each uniform roll through a truncated logistic curve so
+// values near the middle of a range are more common than the extremes.
+const (
+	rollSteepness = 6
+	rollClamp     = 0.002472623
+)
+
+// rollPercentile returns the probability that a roll in [lo, hi] comes out
+// at or below v.
+func rollPercentile(v, lo, hi float64) float64 {
+	if hi <= lo {
+		return 0.5
+	}
+	x := math.Min(math.Max((v-lo)/(hi-lo), 0), 1)
+	p := 1 / (1 + math.Exp(-(x*2-1)*rollSteepness))
+	t := (p - rollClamp) / (1 - 2*rollClamp)
+	return math.Min(math.Max(t, 0), 1)
+}
+
+// rollValue is the inverse of rollPercentile.
+func rollValue(t, lo, hi float64) float64 {
+	p := t*(1-2*rollClamp) + rollClamp
+	x := (-math.Log(1/p-1) + rollSteepness) / (rollSteepness * 2)
+	return lo + x*(hi-lo)
+}
+
+// geyserQuality returns the percentile of g's effective output among every
+// geyser its type can roll, from 0 for the worst to 1 for the best. The
+// output per active cycle and the active share are rolled independently.
+// ok is false for unknown types.
+func geyserQuality(g Geyser) (q float64, ok bool) {
+	t, ok := lookupGeyserType(g.ID)
+	if !ok || t.MaxRate <= 0 {
+		return 0, false
+	}
+	out := g.effectiveOutputPerCycle()
+	// Integrate over the active share roll with the midpoint rule.
+	const steps = 256
+	sum := 0.0
+	for i := 0; i < steps; i++ {
+		a := rollValue((float64(i)+0.5)/steps, t.MinActive, t.MaxActive)
+		sum += rollPercentile(out/a, t.MinRate, t.MaxRate)
+	}
+	return sum / steps, true
+}
+
+// qualityStars converts a quality percentile to a rating from one to five
+// stars.
+func qualityStars(q float64) string {
+	n := min(1+int(q*5), 5)
+	return strings.Repeat("*", n)
+}
+
+// geyserQualityLabel returns text such as "87% ****", or "" for unknown
+// geyser types.
+func geyserQualityLabel(g Geyser) string {
+	q, ok := geyserQuality(g)
+	if !ok {
+		return ""
+	}
+	return formatPercent(q) + " " + qualityStars(q)
+}
+
+func formatPercent(q float64) string {
+	return strconv.Itoa(int(q*100)) + "%"
+}
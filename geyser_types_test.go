@@ -0,0 +1,84 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRollPercentile(t *testing.T) {
+	if got := rollPercentile(1000, 1000, 2000); got > 1e-6 {
+		t.Fatalf("min roll = %v, want 0", got)
+	}
+	if got := rollPercentile(2000, 1000, 2000); math.Abs(got-1) > 1e-6 {
+		t.Fatalf("max roll = %v, want 1", got)
+	}
+	if got := rollPercentile(1500, 1000, 2000); math.Abs(got-0.5) > 1e-9 {
+		t.Fatalf("middle roll = %v, want 0.5", got)
+	}
+	for _, p := range []float64{0.01, 0.25, 0.8} {
+		if got := rollPercentile(rollValue(p, 70, 140), 70, 140); math.Abs(got-p) > 1e-9 {
+			t.Fatalf("rollPercentile(rollValue(%v)) = %v", p, got)
+		}
+	}
+}
+
+func TestGeyserQuality(t *testing.T) {
+	// A Cool Steam Vent emitting 1000-2000 kg per active cycle, active
+	// 40-80% of the time.
+	steam := func(kgPerActiveCycle, activeShare float64) Geyser {
+		return Geyser{
+			ID:             "steam",
+			EmitRate:       kgPerActiveCycle * 1000 / CycleSeconds * 2,
+			EruptionTime:   300,
+			IdleTime:       300,
+			ActiveCycles:   activeShare * 100,
+			DormancyCycles: (1 - activeShare) * 100,
+		}
+	}
+	worst, _ := geyserQuality(steam(1000, 0.4))
+	best, _ := geyserQuality(steam(2000, 0.8))
+	mid, _ := geyserQuality(steam(1500, 0.6))
+	if worst > 0.001 || best < 0.999 {
+		t.Fatalf("worst = %v, best = %v", worst, best)
+	}
+	if mid < 0.4 || mid > 0.6 {
+		t.Fatalf("average roll quality = %v", mid)
+	}
+	if qualityStars(worst) != "*" || qualityStars(best) != "*****" || qualityStars(mid) != "***" {
+		t.Fatalf("unexpected stars %q %q %q", qualityStars(worst), qualityStars(mid), qualityStars(best))
+	}
+	if _, ok := geyserQuality(Geyser{ID: "OilWell"}); ok {
+		t.Fatal("expected no rating for oil reservoirs")
+	}
+	for _, id := range []string{"steam", "hot_steam", "molten_niobium", "oil_drip", "big_volcano"} {
+		if _, ok := lookupGeyserType(id); !ok {
+			t.Errorf("missing reference data for %s", id)
+		}
+	}
+}
+
+func TestGeyserTypeTiming(t *testing.T) {
+	tests := []struct {
+		id                         string
+		minIteration, maxIteration float64
+		minEruption, maxEruption   float64
+	}{
+		{"big_volcano", 6000, 12000, 0.005, 0.01},
+		// The niobium volcano erupts like a magma volcano, not like the
+		// other metal volcanoes.
+		{"molten_niobium", 6000, 12000, 0.005, 0.01},
+		{"molten_iron", 480, 1080, 1.0 / 60, 0.1},
+		{"steam", 60, 1140, 0.1, 0.9},
+	}
+	for _, tt := range tests {
+		info, ok := lookupGeyserType(tt.id)
+		if !ok {
+			t.Errorf("missing reference data for %s", tt.id)
+			continue
+		}
+		if info.MinIteration != tt.minIteration || info.MaxIteration != tt.maxIteration ||
+			info.MinEruption != tt.minEruption || info.MaxEruption != tt.maxEruption {
+			t.Errorf("%s timing = %v-%v s, %v-%v erupting", tt.id, info.MinIteration, info.MaxIteration, info.MinEruption, info.MaxEruption)
+		}
+	}
+}
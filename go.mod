@@ -3,9 +3,11 @@ module oni-view
 go 1.24.3
 
 require (
+	github.com/HugoSmits86/nativewebp v1.2.1
 	github.com/hajimehoshi/ebiten/v2 v2.9.8
 	golang.org/x/image v0.35.0
 	google.golang.org/protobuf v1.36.11
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// gridSteps are the tile spacings the grid chooses from as the zoom changes.
+var gridSteps = []int{1, 2, 5, 10, 50, 100}
+
+// gridStep returns the smallest grid spacing in tiles whose lines are at
+// least minPixels apart at zoom.
+func gridStep(zoom, minPixels float64) int {
+	for _, s := range gridSteps {
+		if float64(s)*2*zoom >= minPixels {
+			return s
+		}
+	}
+	return gridSteps[len(gridSteps)-1]
+}
+
+// gridLineColor returns the color of the grid line at tile i. Every tenth
+// and fiftieth line is drawn stronger.
+func gridLineColor(i int) color.RGBA {
+	switch {
+	case i%50 == 0:
+		return gridMajorColor
+	case i%10 == 0:
+		return gridMediumColor
+	}
+	return gridMinorColor
+}
+
+// drawGrid draws the tile grid over the visible part of the asteroid.
+func (g *Game) drawGrid(dst *ebiten.Image) {
+	if !g.showGrid || g.astWidth == 0 {
+		return
+	}
+	step := gridStep(g.zoom, float64(uiScaled(GridMinSpacing)))
+	scale := 2 * g.zoom
+	x0 := max(0, int(math.Floor(-g.camX/scale)))
+	x1 := min(g.astWidth, int(math.Ceil((float64(g.width)-g.camX)/scale)))
+	y0 := max(0, int(math.Floor(-g.camY/scale)))
+	y1 := min(g.astHeight, int(math.Ceil((float64(g.height)-g.camY)/scale)))
+	if x0 > x1 || y0 > y1 {
+		return
+	}
+	top := float32(float64(y0)*scale + g.camY)
+	bottom := float32(float64(y1)*scale + g.camY)
+	left := float32(float64(x0)*scale + g.camX)
+	right := float32(float64(x1)*scale + g.camX)
+	for x := (x0 + step - 1) / step * step; x <= x1; x += step {
+		sx := float32(math.Round(float64(x)*scale+g.camX)) + 0.5
+		vector.StrokeLine(dst, sx, top, sx, bottom, 1, gridLineColor(x), false)
+	}
+	for y := (y0 + step - 1) / step * step; y <= y1; y += step {
+		sy := float32(math.Round(float64(y)*scale+g.camY)) + 0.5
+		vector.StrokeLine(dst, left, sy, right, sy, 1, gridLineColor(y), false)
+	}
+}
+
+// pointInPolygons reports whether x, y lies inside polys using the even-odd
+// rule, so inner polygons cut holes.
+func pointInPolygons(polys [][]Point, x, y float64) bool {
+	inside := false
+	for _, poly := range polys {
+		for i, j := 0, len(poly)-1; i < len(poly); j, i = i, i+1 {
+			a, b := poly[i], poly[j]
+			ay, by := float64(a.Y), float64(b.Y)
+			if (ay > y) != (by > y) {
+				ax, bx := float64(a.X), float64(b.X)
+				if x < ax+(y-ay)*(bx-ax)/(by-ay) {
+					inside = !inside
+				}
+			}
+		}
+	}
+	return inside
+}
+
+// biomeAt returns the name of the biome containing the world position x, y,
+// or "" outside all biomes.
+func biomeAt(biomes []BiomePath, x, y float64) string {
+	for _, bp := range biomes {
+		if pointInPolygons(bp.Polygons, x, y) {
+			return bp.Name
+		}
+	}
+	return ""
+}
+
+// updateCursorTile tracks the tile and biome under the mouse, or under the
+// screen center when the mouse is outside the window or on touch devices.
+func (g *Game) updateCursorTile(mx, my int) {
+	pt := image.Pt(mx, my)
+	if mx < 0 || g.mobile || g.touchUsed {
+		pt = image.Pt(g.width/2, g.height/2)
+	}
+	x, y := g.screenToWorld(pt)
+	tile := image.Pt(int(math.Floor(x)), int(math.Floor(y)))
+	// The biome only needs a lookup when the tile or the map changed.
+	if tile == g.cursorTile && !g.needsRedraw {
+		return
+	}
+	biome := biomeAt(g.biomes, float64(tile.X)+0.5, float64(tile.Y)+0.5)
+	if tile != g.cursorTile || biome != g.cursorBiome {
+		g.cursorTile = tile
+		g.cursorBiome = biome
+		g.needsRedraw = true
+	}
+}
+
+// statusText describes the cursor tile for the status bar. With flipY the Y
+// coordinate counts up from the bottom of the asteroid like the game's debug
+// coordinates.
+func (g *Game) statusText() string {
+	y := g.cursorTile.Y
+	if g.flipY {
+		y = g.astHeight - 1 - y
+	}
+	s := fmt.Sprintf("X: %d Y: %d", g.cursorTile.X, y)
+	if g.cursorBiome != "" {
+		s += "  " + displayBiome(g.cursorBiome)
+	}
+	return s
+}
@@ -0,0 +1,47 @@
+package main
+
+import "testing"
+
+func TestGridStep(t *testing.T) {
+	cases := []struct {
+		zoom float64
+		want int
+	}{
+		{8, 1},
+		{1, 5},
+		{0.25, 50},
+		{0.01, 100},
+	}
+	for _, c := range cases {
+		if got := gridStep(c.zoom, 8); got != c.want {
+			t.Errorf("gridStep(%v) = %d, want %d", c.zoom, got, c.want)
+		}
+	}
+	if gridLineColor(100) != gridMajorColor || gridLineColor(30) != gridMediumColor || gridLineColor(7) != gridMinorColor {
+		t.Error("unexpected grid line colors")
+	}
+}
+
+func TestBiomeAt(t *testing.T) {
+	square := func(x0, y0, x1, y1 int) []Point {
+		return []Point{{x0, y0}, {x1, y0}, {x1, y1}, {x0, y1}}
+	}
+	biomes := []BiomePath{
+		// A ring with a hole that holds the second biome.
+		{Name: "Sandstone", Polygons: [][]Point{square(0, 0, 20, 20), square(5, 5, 15, 15)}},
+		{Name: "Swamp", Polygons: [][]Point{square(5, 5, 15, 15)}},
+	}
+	cases := []struct {
+		x, y float64
+		want string
+	}{
+		{2.5, 2.5, "Sandstone"},
+		{10.5, 10.5, "Swamp"},
+		{25, 10, ""},
+	}
+	for _, c := range cases {
+		if got := biomeAt(biomes, c.x, c.y); got != c.want {
+			t.Errorf("biomeAt(%v, %v) = %q, want %q", c.x, c.y, got, c.want)
+		}
+	}
+}
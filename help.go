@@ -29,10 +29,20 @@ func init() {
 		{"Click or tap geysers/POIs", "center and show details"},
 		{"Tap legend entries", "highlight items"},
 		{"Camera icon", "open screenshot menu"},
-		{"Geyser-icon", "list all geysers"},
+		{"Geyser-icon", "list, sort and search geysers"},
 		{"Question mark", "toggle this help"},
 		{"X button", "close this help"},
 		{"Gear icon", "open options"},
+		{"Esc while loading", "cancel the download"},
+		{"L key or asteroid menu", "enter a different seed"},
+		{"M key or asteroid menu", "cluster map of all asteroids"},
+		{"R key or asteroid menu", "score against a rule checklist"},
+		{"Ctrl+F or asteroid menu", "find items; Enter/Up/Down step, Tab all asteroids"},
+		{"N key or asteroid menu", "annotate the map with pins, notes and shapes"},
+		{"G key", "toggle the tile grid"},
+		{"Asteroid menu Compare...", "two seeds or asteroids side by side"},
+		{"Asteroid menu Share Link", "copy a link to the current view"},
+		{"Asteroid menu export", "save seed data as JSON or CSV"},
 	}
 	width := 0
 	for _, p := range lines {
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/HugoSmits86/nativewebp"
+)
+
+// imageFormat selects the encoder used for saved images.
+type imageFormat int
+
+const (
+	formatPNG imageFormat = iota
+	formatJPEG
+	formatWebP
+	// formatSVG is vector output written by writeAsteroidSVG instead of
+	// encodeImage.
+	formatSVG
+)
+
+var imageFormatNames = []string{"PNG", "JPEG", "WebP", "SVG"}
+
+func (f imageFormat) String() string {
+	if int(f) < len(imageFormatNames) {
+		return imageFormatNames[f]
+	}
+	return fmt.Sprintf("format %d", int(f))
+}
+
+// ext returns the file extension including the dot.
+func (f imageFormat) ext() string {
+	switch f {
+	case formatJPEG:
+		return ".jpg"
+	case formatWebP:
+		return ".webp"
+	case formatSVG:
+		return ".svg"
+	default:
+		return ".png"
+	}
+}
+
+// parseImageFormat accepts a format name or file extension such as "jpg".
+func parseImageFormat(s string) (imageFormat, error) {
+	switch strings.ToLower(strings.TrimPrefix(s, ".")) {
+	case "png":
+		return formatPNG, nil
+	case "jpg", "jpeg":
+		return formatJPEG, nil
+	case "webp":
+		return formatWebP, nil
+	case "svg":
+		return formatSVG, nil
+	}
+	return formatPNG, fmt.Errorf("unknown image format %q (use png, jpeg, webp or svg)", s)
+}
+
+// imageEncoding bundles an output format with its settings.
+type imageEncoding struct {
+	Format      imageFormat
+	JPEGQuality int
+}
+
+// writeImageFile encodes img to path, creating parent directories.
+func writeImageFile(path string, img image.Image, enc imageEncoding, meta imageMetadata) error {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := encodeImage(f, img, enc.Format, enc.JPEGQuality, meta); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// imageMetadata identifies the seed an image was made from.
+type imageMetadata struct {
+	Coord    string
+	Asteroid string
+	Time     time.Time
+}
+
+// fields returns the metadata as ordered key/value pairs. Keys follow the
+// predefined PNG tEXt keywords where one exists.
+func (m imageMetadata) fields() [][2]string {
+	var out [][2]string
+	if m.Coord != "" {
+		out = append(out, [2]string{"Coordinate", m.Coord})
+	}
+	if m.Asteroid != "" {
+		out = append(out, [2]string{"Asteroid", m.Asteroid})
+	}
+	out = append(out, [2]string{"Software", "Oni-SeedView " + ClientVersion})
+	if !m.Time.IsZero() {
+		out = append(out, [2]string{"Creation Time", m.Time.UTC().Format(time.RFC3339)})
+	}
+	return out
+}
+
+// encodeImage writes img in format. PNG files carry meta as tEXt chunks and
+// JPEG files as a comment segment. WebP output is lossless and has no
+// metadata. jpegQuality is only used for JPEG.
+func encodeImage(w io.Writer, img image.Image, format imageFormat, jpegQuality int, meta imageMetadata) error {
+	var buf bytes.Buffer
+	switch format {
+	case formatPNG:
+		enc := png.Encoder{CompressionLevel: png.BestCompression}
+		if err := enc.Encode(&buf, img); err != nil {
+			return err
+		}
+		out, err := insertPNGText(buf.Bytes(), meta.fields())
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(out)
+		return err
+	case formatJPEG:
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return err
+		}
+		out, err := insertJPEGComment(buf.Bytes(), meta.fields())
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(out)
+		return err
+	case formatWebP:
+		return nativewebp.Encode(w, img, nil)
+	}
+	return fmt.Errorf("unsupported image format %v", format)
+}
+
+// insertPNGText adds a tEXt chunk per field directly after the IHDR chunk.
+func insertPNGText(data []byte, fields [][2]string) ([]byte, error) {
+	// 8 byte signature followed by the 25 byte IHDR chunk.
+	const ihdrEnd = 8 + 4 + 4 + 13 + 4
+	if len(data) < ihdrEnd || string(data[12:16]) != "IHDR" {
+		return nil, errors.New("png: missing IHDR chunk")
+	}
+	var out bytes.Buffer
+	out.Write(data[:ihdrEnd])
+	for _, f := range fields {
+		body := append([]byte(f[0]), 0)
+		body = append(body, latin1(f[1])...)
+		var hdr [8]byte
+		binary.BigEndian.PutUint32(hdr[:4], uint32(len(body)))
+		copy(hdr[4:], "tEXt")
+		out.Write(hdr[:])
+		out.Write(body)
+		crc := crc32.NewIEEE()
+		crc.Write(hdr[4:])
+		crc.Write(body)
+		binary.Write(&out, binary.BigEndian, crc.Sum32())
+	}
+	out.Write(data[ihdrEnd:])
+	return out.Bytes(), nil
+}
+
+// insertJPEGComment adds a COM segment holding fields after the SOI marker.
+func insertJPEGComment(data []byte, fields [][2]string) ([]byte, error) {
+	if len(data) < 2 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil, errors.New("jpeg: missing SOI marker")
+	}
+	var text strings.Builder
+	for i, f := range fields {
+		if i > 0 {
+			text.WriteByte('\n')
+		}
+		text.WriteString(f[0] + ": " + f[1])
+	}
+	com := []byte(text.String())
+	if len(com) > 0xFFFF-2 {
+		com = com[:0xFFFF-2]
+	}
+	var out bytes.Buffer
+	out.Write(data[:2])
+	out.Write([]byte{0xFF, 0xFE})
+	binary.Write(&out, binary.BigEndian, uint16(len(com)+2))
+	out.Write(com)
+	out.Write(data[2:])
+	return out.Bytes(), nil
+}
+
+// latin1 replaces characters tEXt chunks cannot hold.
+func latin1(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		if r > 0xFF || r == 0 {
+			r = '?'
+		}
+		out = append(out, byte(r))
+	}
+	return out
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestEncodeImageMetadata verifies metadata is embedded without breaking the
+// PNG and JPEG streams.
+func TestEncodeImageMetadata(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	meta := imageMetadata{Coord: "SNDST-A-7-0-0-0", Asteroid: "Terra", Time: time.Unix(0, 0)}
+
+	var buf bytes.Buffer
+	if err := encodeImage(&buf, img, formatPNG, 0, meta); err != nil {
+		t.Fatalf("png encode: %v", err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("tEXtCoordinate\x00SNDST-A-7-0-0-0")) {
+		t.Fatal("png is missing the coordinate tEXt chunk")
+	}
+	if _, err := png.Decode(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatalf("png decode: %v", err)
+	}
+
+	buf.Reset()
+	if err := encodeImage(&buf, img, formatJPEG, 90, meta); err != nil {
+		t.Fatalf("jpeg encode: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Asteroid: Terra") {
+		t.Fatal("jpeg is missing the comment segment")
+	}
+	if _, err := jpeg.Decode(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatalf("jpeg decode: %v", err)
+	}
+
+	buf.Reset()
+	if err := encodeImage(&buf, img, formatWebP, 0, meta); err != nil {
+		t.Fatalf("webp encode: %v", err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte("RIFF")) {
+		t.Fatal("webp output has no RIFF header")
+	}
+}
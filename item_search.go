@@ -0,0 +1,387 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Kinds of items found by the search overlay.
+const (
+	findGeyser = "Geyser"
+	findPOI    = "POI"
+	findBiome  = "Biome"
+)
+
+// findMatch is one item found by the search overlay. X and Y are world
+// cells on the asteroid named Asteroid.
+type findMatch struct {
+	Kind     string
+	Name     string
+	Asteroid string
+	X, Y     int
+	score    int
+}
+
+// fuzzyScore rates how well query matches text, ignoring case and spaces in
+// the query. Every query character must appear in order; contiguous runs and
+// word starts rate higher. ok is false when text does not match.
+func fuzzyScore(query, text string) (score int, ok bool) {
+	q := strings.ToLower(strings.Join(strings.Fields(query), ""))
+	t := strings.ToLower(text)
+	if q == "" {
+		return 0, false
+	}
+	if i := strings.Index(t, strings.ToLower(strings.TrimSpace(query))); i >= 0 {
+		score += 100
+		if i == 0 || t[i-1] == ' ' {
+			score += 50
+		}
+	}
+	qr := []rune(q)
+	qi := 0
+	prevMatch := false
+	prev := ' '
+	for _, r := range t {
+		if qi < len(qr) && r == qr[qi] {
+			score++
+			if prevMatch {
+				score += 3
+			}
+			if !unicode.IsLetter(prev) && !unicode.IsDigit(prev) {
+				score += 2
+			}
+			qi++
+			prevMatch = true
+		} else {
+			prevMatch = false
+		}
+		prev = r
+	}
+	if qi < len(qr) {
+		return 0, false
+	}
+	return score, true
+}
+
+// bestScore returns the best fuzzyScore of query against any of names.
+func bestScore(query string, names ...string) (int, bool) {
+	best, found := 0, false
+	for _, n := range names {
+		if n == "" {
+			continue
+		}
+		if s, ok := fuzzyScore(query, n); ok && (!found || s > best) {
+			best, found = s, true
+		}
+	}
+	return best, found
+}
+
+// biomeCenter returns the middle of the bounding box of a biome region.
+func biomeCenter(bp BiomePath) (Point, bool) {
+	first := true
+	var lo, hi Point
+	for _, poly := range bp.Polygons {
+		for _, p := range poly {
+			if first {
+				lo, hi, first = p, p, false
+				continue
+			}
+			lo.X, lo.Y = min(lo.X, p.X), min(lo.Y, p.Y)
+			hi.X, hi.Y = max(hi.X, p.X), max(hi.Y, p.Y)
+		}
+	}
+	return Point{(lo.X + hi.X) / 2, (lo.Y + hi.Y) / 2}, !first
+}
+
+// findItems searches the geysers, POIs and biomes of asts for query by
+// their display names, short names and IDs. Results are ordered by how well
+// they match, then by asteroid and position.
+func findItems(asts []Asteroid, query string) []findMatch {
+	var out []findMatch
+	order := map[string]int{}
+	for i, a := range asts {
+		order[a.ID] = i
+		for _, gy := range a.Geysers {
+			id := simplifyID(gy.ID)
+			if s, ok := bestScore(query, names.Geysers[id], shortNames.Geysers[id], id); ok {
+				out = append(out, findMatch{findGeyser, exportGeyserName(gy.ID), a.ID, gy.X, gy.Y, s})
+			}
+		}
+		for _, p := range a.POIs {
+			id := simplifyID(p.ID)
+			if s, ok := bestScore(query, names.POIs[id], shortNames.POIs[id], id); ok {
+				out = append(out, findMatch{findPOI, exportPOIName(p.ID), a.ID, p.X, p.Y, s})
+			}
+		}
+		for _, bp := range a.BiomePaths.Paths {
+			c, ok := biomeCenter(bp)
+			if !ok {
+				continue
+			}
+			if s, ok := bestScore(query, displayBiome(bp.Name), bp.Name); ok {
+				out = append(out, findMatch{findBiome, displayBiome(bp.Name), a.ID, c.X, c.Y, s})
+			}
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.score != b.score {
+			return a.score > b.score
+		}
+		if a.Asteroid != b.Asteroid {
+			return order[a.Asteroid] < order[b.Asteroid]
+		}
+		if a.Y != b.Y {
+			return a.Y < b.Y
+		}
+		return a.X < b.X
+	})
+	return out
+}
+
+// textInputActive reports whether typed keys go to a text box rather than
+// the keyboard shortcuts.
+func (g *Game) textInputActive() bool {
+	return g.showGeyserList || g.showFind || g.annotEditing
+}
+
+func (g *Game) openFind() {
+	g.closeMenus()
+	g.showFind = true
+	g.updateFindMatches()
+}
+
+func (g *Game) closeFind() {
+	g.showFind = false
+	g.needsRedraw = true
+}
+
+// updateFindMatches reruns the search after the query or scope changed.
+func (g *Game) updateFindMatches() {
+	g.findMatches = nil
+	g.findIndex = -1
+	if strings.TrimSpace(g.findQuery) != "" {
+		asts := g.asteroids
+		if !g.findAll {
+			if idx := asteroidIndexByID(g.asteroids, g.asteroidID); idx >= 0 {
+				asts = asts[idx : idx+1]
+			}
+		}
+		g.findMatches = findItems(asts, g.findQuery)
+	}
+	g.needsRedraw = true
+}
+
+// stepFind moves to the next (delta 1) or previous (delta -1) match, switching
+// asteroid if needed, and flies the camera to it.
+func (g *Game) stepFind(delta int) {
+	n := len(g.findMatches)
+	if n == 0 {
+		return
+	}
+	g.findIndex = ((g.findIndex+delta)%n + n) % n
+	m := g.findMatches[g.findIndex]
+	if m.Asteroid != g.asteroidID {
+		idx := asteroidIndexByID(g.asteroids, m.Asteroid)
+		if idx < 0 {
+			return
+		}
+		g.loadAsteroid(g.asteroids[idx])
+		g.centerAndFit()
+		g.fitOnLoad = false
+	}
+	g.flyTo(float64(m.X), float64(m.Y), m.Kind != findBiome)
+}
+
+// findBarLayout holds the screen rectangles of the search overlay.
+type findBarLayout struct {
+	frame, input, prev, next, all, close image.Rectangle
+}
+
+func (g *Game) findBarLayout() findBarLayout {
+	var l findBarLayout
+	pad := uiScaled(6)
+	bh := menuButtonHeight()
+	w := min(uiScaled(FindBarWidth), g.width-pad*2)
+	x := g.width/2 - w/2
+	y := max(g.asteroidInfoRect().Max.Y, uiScaled(HelpMargin)) + pad
+	l.frame = image.Rect(x, y, x+w, y+bh+pad*2)
+	bx := l.frame.Max.X - pad
+	button := func(label string) image.Rectangle {
+		tw, _ := textDimensions(label)
+		r := image.Rect(bx-tw-pad*2, y+pad, bx, y+pad+bh)
+		bx = r.Min.X - pad/2
+		return r
+	}
+	l.close = image.Rect(bx-bh, y+pad, bx, y+pad+bh)
+	bx = l.close.Min.X - pad/2
+	l.all = button(FindAllLabel)
+	l.next = button("Next")
+	l.prev = button("Prev")
+	l.input = image.Rect(x+pad, y+pad, bx-pad/2, y+pad+bh)
+	return l
+}
+
+// handleFindInput opens the search overlay with Ctrl+F and handles typing,
+// stepping through matches and clicks on the overlay. Typed keys go to the
+// search box but the map stays interactive, so it only returns true when the
+// input was consumed by the overlay.
+func (g *Game) handleFindInput() bool {
+	ctrl := ebiten.IsKeyPressed(ebiten.KeyControl) || ebiten.IsKeyPressed(ebiten.KeyMeta)
+	if ctrl && inpututil.IsKeyJustPressed(ebiten.KeyF) && len(g.asteroids) > 0 && !g.loading && !g.showSeedInput {
+		if g.showFind {
+			g.closeFind()
+		} else {
+			g.openFind()
+		}
+		return true
+	}
+	if !g.showFind {
+		return false
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		g.closeFind()
+		return true
+	}
+	q := g.findQuery
+	if !ctrl {
+		for _, r := range ebiten.AppendInputChars(nil) {
+			if utf8.RuneCountInString(q) < FindQueryMaxLen {
+				q += string(r)
+			}
+		}
+	}
+	if keyRepeat(ebiten.KeyBackspace) && q != "" {
+		_, size := utf8.DecodeLastRuneInString(q)
+		q = q[:len(q)-size]
+	}
+	if q != g.findQuery {
+		g.findQuery = q
+		g.updateFindMatches()
+	}
+	shift := ebiten.IsKeyPressed(ebiten.KeyShift)
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyTab):
+		g.findAll = !g.findAll
+		g.updateFindMatches()
+	case inpututil.IsKeyJustPressed(ebiten.KeyUp),
+		(inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyF3)) && shift:
+		g.stepFind(-1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyDown), inpututil.IsKeyJustPressed(ebiten.KeyEnter),
+		inpututil.IsKeyJustPressed(ebiten.KeyNumpadEnter), inpututil.IsKeyJustPressed(ebiten.KeyF3):
+		g.stepFind(1)
+	}
+
+	var clicks []image.Point
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		mx, my := ebiten.CursorPosition()
+		clicks = append(clicks, image.Pt(mx, my))
+	}
+	for _, id := range inpututil.AppendJustPressedTouchIDs(nil) {
+		x, y := ebiten.TouchPosition(id)
+		clicks = append(clicks, image.Pt(x, y))
+	}
+	l := g.findBarLayout()
+	consumed := false
+	for _, pt := range clicks {
+		if !pt.In(l.frame) {
+			continue
+		}
+		consumed = true
+		switch {
+		case pt.In(l.close):
+			g.closeFind()
+		case pt.In(l.prev):
+			g.stepFind(-1)
+		case pt.In(l.next):
+			g.stepFind(1)
+		case pt.In(l.all):
+			g.findAll = !g.findAll
+			g.updateFindMatches()
+		}
+	}
+	return consumed
+}
+
+// drawFindMarkers circles the matches on the current asteroid.
+func (g *Game) drawFindMarkers(dst *ebiten.Image) {
+	if !g.showFind {
+		return
+	}
+	r := float32(uiScaled(FindMarkerRadius))
+	for i, m := range g.findMatches {
+		if m.Asteroid != g.asteroidID {
+			continue
+		}
+		x := float32(float64(m.X)*2*g.zoom + g.camX)
+		y := float32(float64(m.Y)*2*g.zoom + g.camY)
+		if i == g.findIndex {
+			vector.StrokeCircle(dst, x, y, r*1.5, 3, findMatchColor, true)
+		} else {
+			vector.StrokeCircle(dst, x, y, r, 2, findMatchColor, true)
+		}
+	}
+}
+
+// findStatus describes the current match, e.g. "2/5 Gold Volcano".
+func (g *Game) findStatus() string {
+	switch {
+	case g.findQuery == "":
+		return ""
+	case len(g.findMatches) == 0:
+		return "no matches"
+	case g.findIndex < 0:
+		return fmt.Sprintf("%d matches", len(g.findMatches))
+	}
+	m := g.findMatches[g.findIndex]
+	s := fmt.Sprintf("%d/%d %s", g.findIndex+1, len(g.findMatches), m.Name)
+	if g.findAll {
+		s += " (" + m.Asteroid + ")"
+	}
+	return s
+}
+
+// drawFindBar draws the search overlay.
+func (g *Game) drawFindBar(dst *ebiten.Image) {
+	if !g.showFind {
+		return
+	}
+	l := g.findBarLayout()
+	pad := uiScaled(6)
+	drawFrame(dst, l.frame)
+	lh := menuButtonHeight() - 5
+	if notoFont != nil {
+		lh = notoFont.Metrics().Height.Ceil()
+	}
+	textY := func(r image.Rectangle) int { return r.Min.Y + (r.Dy()-lh)/2 }
+	drawButton(dst, l.input, false)
+	text := "Find: " + g.findQuery + "_"
+	if st := g.findStatus(); st != "" {
+		text += "  " + st
+	}
+	for n := utf8.RuneCountInString(text); n > 8; n-- {
+		if w, _ := textDimensions(truncateString(text, n)); w <= l.input.Dx()-pad*2 {
+			text = truncateString(text, n)
+			break
+		}
+	}
+	drawText(dst, text, l.input.Min.X+pad, textY(l.input), false)
+	for _, b := range []struct {
+		r      image.Rectangle
+		label  string
+		active bool
+	}{{l.prev, "Prev", false}, {l.next, "Next", false}, {l.all, FindAllLabel, g.findAll}} {
+		drawButton(dst, b.r, b.active)
+		drawText(dst, b.label, b.r.Min.X+b.r.Dx()/2, textY(b.r), true)
+	}
+	drawCloseButton(dst, l.close)
+}
@@ -0,0 +1,39 @@
+package main
+
+import "testing"
+
+func TestFuzzyScore(t *testing.T) {
+	if _, ok := fuzzyScore("gold", "Iron Volcano"); ok {
+		t.Error("gold matched Iron Volcano")
+	}
+	if _, ok := fuzzyScore("gdvol", "Gold Volcano"); !ok {
+		t.Error("gdvol did not match Gold Volcano")
+	}
+	exact, _ := fuzzyScore("gold", "Gold Volcano")
+	scattered, ok := fuzzyScore("gold", "Gas Oil Leak Drip")
+	if !ok {
+		t.Fatal("gold did not match Gas Oil Leak Drip")
+	}
+	if exact <= scattered {
+		t.Errorf("substring score %d not above scattered score %d", exact, scattered)
+	}
+}
+
+func TestFindItems(t *testing.T) {
+	asts := []Asteroid{
+		{ID: "Start", Geysers: []Geyser{{ID: "molten_iron", X: 1, Y: 1}, {ID: "molten_gold", X: 5, Y: 9}}},
+		{ID: "Other", Geysers: []Geyser{{ID: "molten_gold", X: 2, Y: 3}},
+			BiomePaths: BiomePathsCompact{Paths: []BiomePath{{Name: "Sandstone", Polygons: [][]Point{{{0, 0}, {10, 0}, {10, 20}, {0, 20}}}}}}},
+	}
+	got := findItems(asts, "gold")
+	if len(got) != 2 || got[0].Asteroid != "Start" || got[1].Asteroid != "Other" || got[0].Kind != findGeyser {
+		t.Fatalf("findItems(gold) = %+v", got)
+	}
+	got = findItems(asts, "sandst")
+	if len(got) != 1 || got[0].Kind != findBiome || got[0].X != 5 || got[0].Y != 10 {
+		t.Fatalf("findItems(sandst) = %+v", got)
+	}
+	if got := findItems(asts, ""); len(got) != 0 {
+		t.Fatalf("empty query matched %d items", len(got))
+	}
+}
@@ -62,11 +62,25 @@ func (g *Game) initObjectLegend() {
 	g.legendMap = make(map[string]int)
 	g.legendColors = nil
 	counter := 1
+	// Rate each geyser type by its best roll on the asteroid.
+	best := make(map[string]float64)
+	for _, gy := range g.geysers {
+		if q, ok := geyserQuality(gy); ok {
+			name := displayGeyser(gy.ID)
+			if prev, seen := best[name]; !seen || q > prev {
+				best[name] = q
+			}
+		}
+	}
 	for _, gy := range g.geysers {
 		name := displayGeyser(gy.ID)
 		if _, ok := g.legendMap["g"+name]; !ok {
 			g.legendMap["g"+name] = counter
-			g.legendEntries = append(g.legendEntries, fmt.Sprintf("%d: %s", counter, name))
+			entry := fmt.Sprintf("%d: %s", counter, name)
+			if q, ok := best[name]; ok {
+				entry += " " + qualityStars(q)
+			}
+			g.legendEntries = append(g.legendEntries, entry)
 			g.legendColors = append(g.legendColors, uniqueColor(counter))
 			counter++
 		}
@@ -134,64 +148,37 @@ func (g *Game) drawNumberLegend(dst *ebiten.Image) {
 }
 
 func (g *Game) buildGeyserItems() {
-	if g.geyserItems != nil && len(g.geyserItems) == len(g.geysers) {
+	if g.geyserItems != nil {
 		return
 	}
-	g.geyserItems = make([]geyserListItem, len(g.geysers))
-	for i, gy := range g.geysers {
+	geysers := g.visibleGeysers()
+	g.geyserItems = make([]geyserListItem, len(geysers))
+	for i, gy := range geysers {
 		ic := (*ebiten.Image)(nil)
 		if n := iconForGeyser(gy.ID); n != "" {
 			ic = g.icons[n]
 		}
-		txt := displayGeyser(gy.ID) + "\n" + formatGeyserInfo(gy)
+		txt := g.geyserInfo(gy)
 		w, h := infoRowSize(txt, ic)
-		g.geyserItems[i] = geyserListItem{text: txt, icon: ic, w: w, h: h}
+		g.geyserItems[i] = geyserListItem{geyser: gy, text: txt, icon: ic, w: w, h: h}
 	}
 }
 
 func (g *Game) drawGeyserList(dst *ebiten.Image) {
 	vector.DrawFilledRect(dst, 0, 0, float32(g.width), float32(g.height), color.RGBA{0, 0, 0, 255}, false)
-	cr := g.geyserCloseRect()
-	drawCloseButton(dst, cr)
 
-	spacing := uiScaled(geyserRowSpace)
-	g.buildGeyserItems()
-	maxW := 0
-	for _, it := range g.geyserItems {
-		if it.w > maxW {
-			maxW = it.w
-		}
-	}
-	cols := 1
-	if maxW+spacing > 0 {
-		cols = g.width / (maxW + spacing)
-		if cols < 1 {
-			cols = 1
-		}
-	}
-	rows := (len(g.geyserItems) + cols - 1) / cols
-	rowHeights := make([]int, rows)
+	rects, _ := g.geyserListLayout()
 	for i, it := range g.geyserItems {
-		r := i / cols
-		if it.h > rowHeights[r] {
-			rowHeights[r] = it.h
+		r := rects[i].Add(image.Pt(0, -int(g.geyserScroll)))
+		if r.Max.Y > 0 && r.Min.Y < g.height && r.Max.X > 0 && r.Min.X < g.width {
+			g.drawInfoRow(dst, it.text, it.icon, r.Min.X, r.Min.Y)
 		}
 	}
-	y := spacing - int(g.geyserScroll)
-	idx := 0
-	for r := 0; r < rows; r++ {
-		rowH := rowHeights[r]
-		x := spacing
-		for c := 0; c < cols && idx < len(g.geyserItems); c++ {
-			it := g.geyserItems[idx]
-			if y+rowH > 0 && y < g.height && x+it.w > 0 && x < g.width {
-				g.drawInfoRow(dst, it.text, it.icon, x, y)
-			}
-			x += maxW + spacing
-			idx++
-		}
-		y += rowH + spacing
+	if len(g.geyserItems) == 0 {
+		drawText(dst, "No geysers match", g.width/2, g.geyserListHeader().height+uiScaled(geyserRowSpace), true)
 	}
+	g.drawGeyserListHeader(dst)
+	drawCloseButton(dst, g.geyserCloseRect())
 
 	if max := g.maxGeyserScroll(); max > 0 {
 		barW := uiScaled(ScrollBarWidth)
@@ -211,38 +198,8 @@ func (g *Game) drawGeyserList(dst *ebiten.Image) {
 }
 
 func (g *Game) maxGeyserScroll() float64 {
-	spacing := uiScaled(geyserRowSpace)
-	g.buildGeyserItems()
-	maxW := 0
-	for _, it := range g.geyserItems {
-		if it.w > maxW {
-			maxW = it.w
-		}
-	}
-	cols := 1
-	if maxW+spacing > 0 {
-		cols = g.width / (maxW + spacing)
-		if cols < 1 {
-			cols = 1
-		}
-	}
-	rows := (len(g.geyserItems) + cols - 1) / cols
-	if rows == 0 {
-		return 0
-	}
-	rowHeights := make([]int, rows)
-	for i, it := range g.geyserItems {
-		r := i / cols
-		if it.h > rowHeights[r] {
-			rowHeights[r] = it.h
-		}
-	}
-	total := spacing
-	for _, h := range rowHeights {
-		total += h + spacing
-	}
-	extra := rowHeights[rows-1] + spacing
-	max := float64(total - g.height + extra)
+	_, total := g.geyserListLayout()
+	max := float64(total - g.height)
 	if max < 0 {
 		max = 0
 	}
@@ -3,6 +3,7 @@ package main
 import (
 	"flag"
 	"fmt"
+	"os"
 	"runtime"
 	"strings"
 
@@ -10,20 +11,56 @@ import (
 )
 
 func main() {
+	if code, ok := runCommand(os.Args[1:]); ok {
+		os.Exit(code)
+	}
 	coord := flag.String("coord", "V-FRST-C-1331877-0-0-0", "seed coordinate")
-	screenshot := flag.String("screenshot", "", "path to save a PNG screenshot and exit")
+	asteroid := flag.String("asteroid", "", "asteroid ID to show (default: the starting asteroid)")
+	screenshot := flag.String("screenshot", "", "render the map to a PNG file without opening a window and exit")
+	src := addSeedSourceFlags(flag.CommandLine)
+	flag.StringVar(&scoreRulesPath, "rules", "", "YAML or JSON rule file for the score panel (default: the built-in checklist)")
+	compare := flag.String("compare", "", "open side-by-side with another seed coordinate, or the same one to compare two of its asteroids")
+	file := flag.String("file", "", "load a seed from a local .pb or .pb.gz file instead of the network")
+	link := flag.String("url", "", "open a view link copied with Share Link, such as \"view.html?coord=...&x=...\"")
+	flag.StringVar(&shareBase, "share-base", DefaultShareBase, "viewer address that Share Link builds links on")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags]\n       %s COMMAND [flags] (commands: %s)\n", os.Args[0], os.Args[0], commandNames())
+		flag.PrintDefaults()
+	}
 	flag.Parse()
+	src.apply()
+	if *file != "" && !flagPassed("coord") {
+		*coord = coordFromFileName(*file)
+	}
 	asteroidIDVal := ""
 	asteroidSpecified := false
+	if *asteroid != "" {
+		asteroidIDVal = normalizeAsteroidID(*asteroid)
+		asteroidSpecified = true
+	}
+	if *screenshot != "" {
+		os.Exit(renderScreenshot(*coord, *file, asteroidIDVal, *screenshot))
+	}
+	var pending *viewState
 	if runtime.GOARCH == "wasm" {
-		if c := coordFromURL(); c != "" {
-			c = strings.TrimSpace(c)
-			*coord = c
+		*link = pageURL()
+	}
+	if *link != "" {
+		v, err := parseViewLink(*link)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "url:", err)
+			if runtime.GOARCH != "wasm" {
+				os.Exit(2)
+			}
+		}
+		if v.Coord != "" {
+			*coord = v.Coord
 		}
-		if a, ok := asteroidFromURL(); ok {
-			asteroidIDVal = a
+		if v.Asteroid != "" {
+			asteroidIDVal = v.Asteroid
 			asteroidSpecified = true
 		}
+		pending = &v
 	}
 
 	game := &Game{
@@ -34,41 +71,32 @@ func main() {
 		minZoom:           MinZoom,
 		loading:           true,
 		status:            "Fetching...",
+		loadFraction:      -1,
 		statusError:       false,
 		coord:             *coord,
+		seedFile:          *file,
 		asteroidID:        asteroidIDVal,
 		asteroidSpecified: asteroidSpecified,
-		textures:          true,
-		vsync:             true,
-		showItemNames:     true,
-		showLegend:        true,
+		ssAnnotations:     true,
 		mobile:            isMobile(),
-		useNumbers:        !isMobile(),
-		iconScale:         1.0,
-		smartRender:       true,
-		linearFilter:      true,
-		hidpi:             true,
 		ssQuality:         1,
+		ssJPEGQuality:     1,
 		hoverBiome:        -1,
 		hoverItem:         -1,
 		selectedBiome:     -1,
 		selectedItem:      -1,
+		pendingCompare:    strings.ToUpper(strings.TrimSpace(*compare)),
+		pendingView:       pending,
+	}
+	game.applySettings(loadSettings(game.mobile))
+	if pending != nil {
+		game.applyLinkLayers(*pending)
 	}
-	setHiDPI(game.hidpi)
 	registerFontChange(game.invalidateLegends)
 	loadGameData(game, *coord, asteroidIDVal)
-	if *screenshot != "" {
-
-		game.screenshotPath = *screenshot
-		//game.screenshotMode = true
-		//game.showHelp = false
-		//game.showInfo = false
-		game.showShotMenu = false
-
-	}
 	ebiten.SetWindowSize(game.width, game.height)
 	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
-	ebiten.SetWindowTitle("Geysers - " + *coord)
+	ebiten.SetWindowTitle(windowTitle(*coord))
 	ebiten.SetScreenClearedEveryFrame(false)
 	ebiten.SetVsyncEnabled(game.vsync)
 	if err := ebiten.RunGame(game); err != nil {
@@ -76,86 +104,22 @@ func main() {
 	}
 }
 
-func loadGameData(game *Game, coord, asteroidID string) {
-	data, err := fetchSeedProto(coord)
-	if err != nil {
-		game.status = "Error: " + err.Error()
-		game.statusError = false
-		game.needsRedraw = true
-		game.loading = false
-		return
-	}
-	seed, err := decodeSeedProto(data)
-	if err != nil {
-		game.status = "Error: " + err.Error()
-		game.statusError = false
-		game.needsRedraw = true
-		game.loading = false
-		return
-	}
-	game.asteroids = seed.Asteroids
-	astIdxSel := 0
-	if game.asteroidSpecified {
-		astIdxSel = asteroidIndexByID(seed.Asteroids, asteroidID)
-		if astIdxSel < 0 {
-			game.status = fmt.Sprintf("%s\nAsteroid ID: %s\nThis location does not contain Asteroid ID: %s", game.coord, asteroidID, asteroidID)
-			if len(seed.Asteroids) > 0 {
-				valid := make([]string, 0, len(seed.Asteroids))
-				for _, a := range seed.Asteroids {
-					valid = append(valid, a.ID)
-				}
-				lines := make([]string, 0, (len(valid)+2)/3)
-				for i, v := range valid {
-					if i%3 == 0 {
-						lines = append(lines, v)
-					} else {
-						lines[len(lines)-1] = lines[len(lines)-1] + ", " + v
-					}
-				}
-				game.status += fmt.Sprintf("\nValid IDs: %s", strings.Join(lines, "\n"))
-			}
-			game.statusError = true
-			game.needsRedraw = true
-			game.loading = false
-			return
-		}
-	}
-	ast := seed.Asteroids[astIdxSel]
-	game.invalidateLegends()
-	game.legendMap = nil
-	game.legendEntries = nil
-	game.legendColors = nil
-	game.selectedItem = -1
-	game.itemScroll = 0
-	game.asteroidID = ast.ID
-	bps := ast.BiomePaths.Paths
-	game.geysers = ast.Geysers
-	game.pois = ast.POIs
-	game.biomes = bps
-	game.astWidth = ast.SizeX
-	game.astHeight = ast.SizeY
-	game.legend, game.legendBiomes = buildLegendImage(bps)
-	game.fitOnLoad = true
-	game.biomeTextures = loadBiomeTextures()
-	names := []string{"../icons/camera.png", "../icons/help.png", "../icons/gear.png", "geyser_water.png"}
-	set := make(map[string]struct{})
-	for _, gy := range ast.Geysers {
-		if n := iconForGeyser(gy.ID); n != "" {
-			if _, ok := set[n]; !ok {
-				set[n] = struct{}{}
-				names = append(names, n)
-			}
-		}
+// windowTitle returns the window title shown for coord, including the
+// cluster name when the coordinate is valid.
+func windowTitle(coord string) string {
+	if d := describeCoordinate(coord); d != "" {
+		return "Geysers - " + d + " (" + coord + ")"
 	}
-	for _, poi := range ast.POIs {
-		if n := iconForPOI(poi.ID); n != "" {
-			if _, ok := set[n]; !ok {
-				set[n] = struct{}{}
-				names = append(names, n)
-			}
+	return "Geysers - " + coord
+}
+
+// flagPassed reports whether the named flag was set on the command line.
+func flagPassed(name string) bool {
+	found := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			found = true
 		}
-	}
-	game.startIconLoader(names)
-	game.loading = false
-	game.needsRedraw = true
+	})
+	return found
 }
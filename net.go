@@ -2,12 +2,15 @@ package main
 
 import (
 	"compress/gzip"
+	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"google.golang.org/protobuf/proto"
 	seedpb "oni-view/data/pb"
@@ -78,38 +81,208 @@ func geyserTypeFromID(id int32) string {
 	}
 }
 
+// errSeedNotModified is returned by fetchSeedProtoETag when the server reports
+// that the cached copy matching the supplied ETag is still current.
+var errSeedNotModified = errors.New("seed not modified")
+
+// seedFetchPolicy controls how long a single seed request may take and how
+// failed requests are retried.
+type seedFetchPolicy struct {
+	// Timeout bounds each individual request attempt.
+	Timeout time.Duration
+	// MaxRetries is the number of additional attempts after the first.
+	MaxRetries int
+	// BaseDelay is the backoff before the first retry. It doubles for each
+	// further attempt up to MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+var seedProtoFetchPolicy = seedFetchPolicy{
+	Timeout:    SeedFetchTimeout,
+	MaxRetries: SeedFetchRetries,
+	BaseDelay:  SeedFetchBaseDelay,
+	MaxDelay:   SeedFetchMaxDelay,
+}
+
+// seedHTTPError reports a non-success HTTP status from the seed server.
+type seedHTTPError struct {
+	StatusCode int
+	Body       []byte
+	RetryAfter string
+}
+
+func (e *seedHTTPError) Error() string {
+	return fmt.Sprintf("unexpected status %d: %s", e.StatusCode, e.Body)
+}
+
+// retryable reports whether the request may succeed when repeated.
+func (e *seedHTTPError) retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+type downloadProgressKey struct{}
+
+// withDownloadProgress returns a context that makes seed downloads report the
+// number of body bytes received to fn. total is -1 when the server did not
+// send a Content-Length.
+func withDownloadProgress(ctx context.Context, fn func(read, total int64)) context.Context {
+	return context.WithValue(ctx, downloadProgressKey{}, fn)
+}
+
+// progressReader counts bytes read from r and reports them to fn.
+type progressReader struct {
+	r     io.Reader
+	read  int64
+	total int64
+	fn    func(read, total int64)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.read += int64(n)
+		p.fn(p.read, p.total)
+	}
+	return n, err
+}
+
 // fetchSeedProto retrieves the seed data in protobuf format for a given coordinate.
 // It requests the protobuf endpoint and transparently decompresses gzip-encoded responses.
 func fetchSeedProto(coordinate string) ([]byte, error) {
+	body, _, err := fetchSeedProtoETag(coordinate, "")
+	return body, err
+}
+
+// fetchSeedProtoETag behaves like fetchSeedProto but sends If-None-Match when
+// etag is set and returns the ETag of the response.
+func fetchSeedProtoETag(coordinate, etag string) ([]byte, string, error) {
+	return fetchSeedProtoContext(context.Background(), coordinate, etag, nil)
+}
+
+// fetchSeedProtoContext downloads a seed using seedProtoFetchPolicy. Server
+// errors (5xx), rate limiting (429), timeouts and network failures are retried
+// with exponential backoff, honouring Retry-After when present. status, if
+// non-nil, receives a message before every attempt and backoff wait.
+func fetchSeedProtoContext(ctx context.Context, coordinate, etag string, status func(string)) ([]byte, string, error) {
 	base := strings.TrimSuffix(seedProtoBaseURL, "/")
 	url := base + "/" + coordinate
-	req, _ := http.NewRequest("GET", url, nil)
+	policy := seedProtoFetchPolicy
+	attempts := policy.MaxRetries + 1
+	for attempt := 1; ; attempt++ {
+		if status != nil {
+			if attempt == 1 {
+				status("Fetching...")
+			} else {
+				status(fmt.Sprintf("Fetching... (attempt %d of %d)", attempt, attempts))
+			}
+		}
+		body, newETag, err := fetchSeedProtoOnce(ctx, url, etag, policy.Timeout)
+		if err == nil || errors.Is(err, errSeedNotModified) {
+			return body, newETag, err
+		}
+		if ctx.Err() != nil {
+			return nil, "", ctx.Err()
+		}
+		retryAfter := ""
+		var httpErr *seedHTTPError
+		if errors.As(err, &httpErr) {
+			if !httpErr.retryable() {
+				return nil, "", err
+			}
+			retryAfter = httpErr.RetryAfter
+		}
+		if attempt >= attempts {
+			if attempts > 1 {
+				return nil, "", fmt.Errorf("%v (gave up after %d attempts)", err, attempts)
+			}
+			return nil, "", err
+		}
+		delay := retryDelay(policy, attempt, retryAfter, time.Now())
+		if status != nil {
+			status(fmt.Sprintf("Fetch failed: %v\nRetrying in %s (attempt %d of %d)", err, delay.Round(time.Millisecond), attempt+1, attempts))
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, "", ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// retryDelay returns how long to wait before the next attempt. A valid
+// Retry-After header (seconds or HTTP date) takes precedence over exponential
+// backoff; both are capped at the policy's MaxDelay.
+func retryDelay(policy seedFetchPolicy, attempt int, retryAfter string, now time.Time) time.Duration {
+	delay := policy.BaseDelay
+	for i := 1; i < attempt && delay < policy.MaxDelay; i++ {
+		delay *= 2
+	}
+	if retryAfter != "" {
+		if secs, err := strconv.Atoi(strings.TrimSpace(retryAfter)); err == nil && secs >= 0 {
+			delay = time.Duration(secs) * time.Second
+		} else if t, err := http.ParseTime(retryAfter); err == nil {
+			delay = t.Sub(now)
+			if delay < 0 {
+				delay = 0
+			}
+		}
+	}
+	if policy.MaxDelay > 0 && delay > policy.MaxDelay {
+		delay = policy.MaxDelay
+	}
+	return delay
+}
+
+// seedFetchFallbackTimeout bounds an attempt when the policy timeout is not
+// positive, so a request can never hang.
+var seedFetchFallbackTimeout = SeedFetchTimeout
+
+// fetchSeedProtoOnce performs a single request for url bounded by timeout.
+func fetchSeedProtoOnce(ctx context.Context, url, etag string, timeout time.Duration) ([]byte, string, error) {
+	if timeout <= 0 {
+		timeout = seedFetchFallbackTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", url, nil)
 	req.Header.Set("Accept", AcceptProtoHeader)
 	req.Header.Set("Accept-Encoding", GzipEncoding)
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
 
 	resp, err := seedProtoHTTPClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %v", err)
+		return nil, "", fmt.Errorf("request failed: %v", err)
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, etag, errSeedNotModified
+	}
 
 	var reader io.Reader = resp.Body
+	if fn, ok := ctx.Value(downloadProgressKey{}).(func(read, total int64)); ok {
+		reader = &progressReader{r: resp.Body, total: resp.ContentLength, fn: fn}
+	}
 	if resp.Header.Get("Content-Encoding") == GzipEncoding {
-		gz, err := gzip.NewReader(resp.Body)
+		gz, err := gzip.NewReader(reader)
 		if err != nil {
-			return nil, fmt.Errorf("gzip init failed: %v", err)
+			return nil, "", fmt.Errorf("gzip init failed: %v", err)
 		}
 		defer gz.Close()
 		reader = gz
 	}
 	body, err := io.ReadAll(reader)
 	if err != nil {
-		return nil, fmt.Errorf("read failed: %v", err)
+		return nil, "", fmt.Errorf("read failed: %v", err)
 	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, body)
+		return nil, "", &seedHTTPError{StatusCode: resp.StatusCode, Body: body, RetryAfter: resp.Header.Get("Retry-After")}
 	}
-	return body, nil
+	return body, resp.Header.Get("ETag"), nil
 }
 
 // decodeSeedProto parses the protobuf seed data into SeedData.
@@ -121,9 +294,12 @@ func decodeSeedProto(protoData []byte) (*SeedData, error) {
 	seed := &SeedData{}
 	for _, a := range pb.Asteroids {
 		ast := Asteroid{
-			ID:    asteroidNameFromID(a.Id),
-			SizeX: int(a.SizeX),
-			SizeY: int(a.SizeY),
+			ID:      asteroidNameFromID(a.Id),
+			SizeX:   int(a.SizeX),
+			SizeY:   int(a.SizeY),
+			OffsetX: int(a.OffsetX),
+			OffsetY: int(a.OffsetY),
+			Traits:  decodeWorldTraits(a.WorldTraitsBitmask),
 		}
 		for _, g := range a.Geysers {
 			ast.Geysers = append(ast.Geysers, Geyser{
@@ -151,6 +327,9 @@ func decodeSeedProto(protoData []byte) (*SeedData, error) {
 	return seed, nil
 }
 
+// newSeedProtoHTTPClient returns the client used for seed downloads. It has
+// no timeout of its own: each attempt is bounded by the context deadline of
+// seedProtoFetchPolicy, which -timeout sets, see fetchSeedProtoOnce.
 func newSeedProtoHTTPClient() *http.Client {
 	if IgnoreSeedProtoCertErrors {
 		return &http.Client{
@@ -161,7 +340,7 @@ func newSeedProtoHTTPClient() *http.Client {
 			},
 		}
 	}
-	return http.DefaultClient
+	return &http.Client{}
 }
 
 // parseBiomePaths decodes the compact biome path string format into a
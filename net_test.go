@@ -2,9 +2,14 @@ package main
 
 import (
 	"compress/gzip"
+	"context"
+	"flag"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"google.golang.org/protobuf/proto"
 	seedpb "oni-view/data/pb"
@@ -56,6 +61,8 @@ func TestDecodeSeedProto(t *testing.T) {
 				Geysers:          []*seedpb.Geyser{{Id: 0, X: 1, Y: 2}},
 				PointsOfInterest: []*seedpb.PointOfInterest{{Id: seedpb.PointOfInterestType_Headquarters, X: 3, Y: 4}},
 				BiomePaths:       "3:1 2 2 2",
+				// GeoActive (bit 6) and MetalPoor (bit 12).
+				WorldTraitsBitmask: 1<<6 | 1<<12,
 			},
 		},
 	}
@@ -83,6 +90,9 @@ func TestDecodeSeedProto(t *testing.T) {
 	if len(a.BiomePaths.Paths) != 1 || a.BiomePaths.Paths[0].Name != "Sandstone" {
 		t.Fatalf("unexpected biome paths: %+v", a.BiomePaths)
 	}
+	if worldTraitNames(a.Traits) != "Geoactive, Metal Poor" {
+		t.Fatalf("unexpected traits: %v", a.Traits)
+	}
 	bp := a.BiomePaths.Paths[0]
 	if len(bp.Polygons) != 1 || len(bp.Polygons[0]) != 2 {
 		t.Fatalf("unexpected polygon count: %+v", bp.Polygons)
@@ -92,3 +102,184 @@ func TestDecodeSeedProto(t *testing.T) {
 		t.Fatalf("unexpected polygon points: %+v", p0)
 	}
 }
+
+// useTestFetchPolicy installs a fast retry policy for the duration of a test.
+func useTestFetchPolicy(t *testing.T, srvURL string, policy seedFetchPolicy) {
+	t.Helper()
+	oldPolicy, oldURL := seedProtoFetchPolicy, seedProtoBaseURL
+	seedProtoFetchPolicy = policy
+	seedProtoBaseURL = srvURL + "/"
+	t.Cleanup(func() {
+		seedProtoFetchPolicy, seedProtoBaseURL = oldPolicy, oldURL
+	})
+}
+
+// TestFetchSeedProtoRetriesServerErrors verifies 5xx and 429 responses are
+// retried and that status messages report the attempt number.
+func TestFetchSeedProtoRetriesServerErrors(t *testing.T) {
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		switch hits {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.Write([]byte("ok"))
+		}
+	}))
+	defer srv.Close()
+	useTestFetchPolicy(t, srv.URL, seedFetchPolicy{Timeout: time.Second, MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond})
+
+	var msgs []string
+	body, _, err := fetchSeedProtoContext(context.Background(), "test", "", func(s string) { msgs = append(msgs, s) })
+	if err != nil {
+		t.Fatalf("fetch error: %v", err)
+	}
+	if string(body) != "ok" || hits != 3 {
+		t.Fatalf("unexpected body %q after %d requests", body, hits)
+	}
+	if last := msgs[len(msgs)-1]; !strings.Contains(last, "attempt 3 of 4") {
+		t.Fatalf("unexpected status message: %q", last)
+	}
+}
+
+// TestFetchSeedProtoNoRetryOnClientError verifies 4xx responses fail immediately.
+func TestFetchSeedProtoNoRetryOnClientError(t *testing.T) {
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+	useTestFetchPolicy(t, srv.URL, seedFetchPolicy{Timeout: time.Second, MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+
+	if _, err := fetchSeedProto("test"); err == nil {
+		t.Fatal("expected error")
+	}
+	if hits != 1 {
+		t.Fatalf("expected 1 request, got %d", hits)
+	}
+}
+
+// TestFetchSeedProtoTimeout verifies hung requests time out and are retried
+// until the retry budget is exhausted.
+func TestFetchSeedProtoTimeout(t *testing.T) {
+	release := make(chan struct{})
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+	useTestFetchPolicy(t, srv.URL, seedFetchPolicy{Timeout: 20 * time.Millisecond, MaxRetries: 1, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+
+	_, err := fetchSeedProto("test")
+	if err == nil || !strings.Contains(err.Error(), "gave up after 2 attempts") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hits != 2 {
+		t.Fatalf("expected 2 requests, got %d", hits)
+	}
+}
+
+// TestFetchSeedProtoZeroTimeout verifies a request to a server that never
+// responds still ends when the policy has no timeout.
+func TestFetchSeedProtoZeroTimeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+	useTestFetchPolicy(t, srv.URL, seedFetchPolicy{Timeout: 0, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+	old := seedFetchFallbackTimeout
+	seedFetchFallbackTimeout = 20 * time.Millisecond
+	defer func() { seedFetchFallbackTimeout = old }()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := fetchSeedProto("test")
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("expected a timeout error")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("fetch with timeout 0 did not return")
+	}
+}
+
+// TestTimeoutFlagRejectsNonPositive verifies -timeout must be positive.
+func TestTimeoutFlagRejectsNonPositive(t *testing.T) {
+	old := seedProtoFetchPolicy
+	defer func() { seedProtoFetchPolicy = old }()
+	for _, v := range []string{"0", "-5s"} {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		addSeedSourceFlags(fs)
+		if err := fs.Parse([]string{"-timeout", v}); err == nil {
+			t.Errorf("-timeout %s accepted", v)
+		}
+	}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	addSeedSourceFlags(fs)
+	if err := fs.Parse([]string{"-timeout", "3m"}); err != nil || seedProtoFetchPolicy.Timeout != 3*time.Minute {
+		t.Errorf("-timeout 3m: timeout %v, error %v", seedProtoFetchPolicy.Timeout, err)
+	}
+}
+
+// TestFetchSeedProtoCancel verifies cancelling the context stops retries.
+func TestFetchSeedProtoCancel(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+	useTestFetchPolicy(t, srv.URL, seedFetchPolicy{Timeout: time.Second, MaxRetries: 5, BaseDelay: time.Hour, MaxDelay: time.Hour})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	status := func(s string) {
+		if strings.HasPrefix(s, "Fetch failed") {
+			cancel()
+		}
+	}
+	if _, _, err := fetchSeedProtoContext(ctx, "test", "", status); err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+// TestRetryDelay checks exponential backoff and Retry-After handling.
+func TestRetryDelay(t *testing.T) {
+	policy := seedFetchPolicy{BaseDelay: time.Second, MaxDelay: 10 * time.Second}
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		attempt    int
+		retryAfter string
+		want       time.Duration
+	}{
+		{1, "", time.Second},
+		{2, "", 2 * time.Second},
+		{3, "", 4 * time.Second},
+		{6, "", 10 * time.Second},
+		{1, "7", 7 * time.Second},
+		{1, "120", 10 * time.Second},
+		{1, now.Add(3 * time.Second).Format(http.TimeFormat), 3 * time.Second},
+		{2, "soon", 2 * time.Second},
+	}
+	for _, c := range cases {
+		if got := retryDelay(policy, c.attempt, c.retryAfter, now); got != c.want {
+			t.Errorf("retryDelay(%d, %q) = %v, want %v", c.attempt, c.retryAfter, got, c.want)
+		}
+	}
+}
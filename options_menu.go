@@ -21,6 +21,10 @@ func (g *Game) optionsMenuSize() (int, int) {
 		"Show Item Names",
 		"Show Legends",
 		"Use Item Numbers",
+		"Pod Lines",
+		"Show Annotations",
+		"Tile Grid",
+		"Flip Y",
 		"Icon Size [-] [+]",
 		uiLabel,
 		"Textures",
@@ -28,6 +32,7 @@ func (g *Game) optionsMenuSize() (int, int) {
 		"Power Saver",
 		"Linear Filtering",
 		"HiDPI",
+		ResetSettingsLabel,
 		"FPS: 60.0",
 		"Version: " + ClientVersion,
 		"GitHub: Distortions81/ONI-SeedView",
@@ -89,6 +94,10 @@ func (g *Game) drawOptionsMenu(dst *ebiten.Image) {
 	drawToggle("Show Item Names", g.showItemNames)
 	drawToggle("Show Legends", g.showLegend)
 	drawToggle("Use Item Numbers", g.useNumbers)
+	drawToggle("Pod Lines", g.showPodLines)
+	drawToggle("Show Annotations", g.showAnnotations)
+	drawToggle("Tile Grid", g.showGrid)
+	drawToggle("Flip Y", g.flipY)
 
 	label := "Icon Size"
 	drawText(img, label, pad, y, false)
@@ -122,6 +131,7 @@ func (g *Game) drawOptionsMenu(dst *ebiten.Image) {
 	drawToggle("Power Saver", g.smartRender)
 	drawToggle("Linear Filtering", g.linearFilter)
 	drawToggle("HiDPI", g.hidpi)
+	drawToggle(ResetSettingsLabel, false)
 
 	fps := fmt.Sprintf("FPS: %.1f", ebiten.ActualFPS())
 	drawText(img, fps, pad, y, false)
@@ -190,6 +200,42 @@ func (g *Game) clickOptionsMenu(mx, my int) bool {
 	}
 	y += menuSpacing()
 
+	// Pod Lines
+	r = image.Rect(uiScaled(4), y-uiScaled(4), w-uiScaled(4), y-uiScaled(4)+menuButtonHeight())
+	if r.Overlaps(image.Rect(mx, my, mx+1, my+1)) {
+		g.showPodLines = !g.showPodLines
+		g.needsRedraw = true
+		return true
+	}
+	y += menuSpacing()
+
+	// Show Annotations
+	r = image.Rect(uiScaled(4), y-uiScaled(4), w-uiScaled(4), y-uiScaled(4)+menuButtonHeight())
+	if r.Overlaps(image.Rect(mx, my, mx+1, my+1)) {
+		g.showAnnotations = !g.showAnnotations
+		g.needsRedraw = true
+		return true
+	}
+	y += menuSpacing()
+
+	// Tile Grid
+	r = image.Rect(uiScaled(4), y-uiScaled(4), w-uiScaled(4), y-uiScaled(4)+menuButtonHeight())
+	if r.Overlaps(image.Rect(mx, my, mx+1, my+1)) {
+		g.showGrid = !g.showGrid
+		g.needsRedraw = true
+		return true
+	}
+	y += menuSpacing()
+
+	// Flip Y
+	r = image.Rect(uiScaled(4), y-uiScaled(4), w-uiScaled(4), y-uiScaled(4)+menuButtonHeight())
+	if r.Overlaps(image.Rect(mx, my, mx+1, my+1)) {
+		g.flipY = !g.flipY
+		g.needsRedraw = true
+		return true
+	}
+	y += menuSpacing()
+
 	// Icon Size buttons
 	labelW, _ := textDimensions("Icon Size")
 	bx := uiScaled(6) + labelW + uiScaled(6)
@@ -275,6 +321,14 @@ func (g *Game) clickOptionsMenu(mx, my int) bool {
 	}
 	y += menuSpacing()
 
+	// Reset to Defaults
+	r = image.Rect(uiScaled(4), y-uiScaled(4), w-uiScaled(4), y-uiScaled(4)+menuButtonHeight())
+	if r.Overlaps(image.Rect(mx, my, mx+1, my+1)) {
+		g.resetSettings()
+		return true
+	}
+	y += menuSpacing()
+
 	// FPS (not clickable)
 	y += menuSpacing()
 
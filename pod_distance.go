@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// findPrintingPod returns the position of the Printing Pod (the Headquarters
+// POI) among pois.
+func findPrintingPod(pois []PointOfInterest) (Point, bool) {
+	for _, p := range pois {
+		if simplifyID(p.ID) == "Headquarters" {
+			return Point{p.X, p.Y}, true
+		}
+	}
+	return Point{}, false
+}
+
+// printingPod returns the position of the asteroid's Printing Pod.
+func printingPod(a Asteroid) (Point, bool) {
+	return findPrintingPod(a.POIs)
+}
+
+// tileDistance is the straight line distance between two cells in tiles.
+func tileDistance(a, b Point) float64 {
+	return math.Hypot(float64(a.X-b.X), float64(a.Y-b.Y))
+}
+
+// manhattanDistance is the number of tiles walked along the grid between two
+// cells, ignoring obstacles.
+func manhattanDistance(a, b Point) int {
+	dx, dy := a.X-b.X, a.Y-b.Y
+	if dx < 0 {
+		dx = -dx
+	}
+	if dy < 0 {
+		dy = -dy
+	}
+	return dx + dy
+}
+
+var podDirections = []string{"right", "down right", "down", "down left", "left", "up left", "up", "up right"}
+
+// podDirection names the direction of p as seen from the pod in one of eight
+// sectors. Map Y coordinates grow downwards.
+func podDirection(pod, p Point) string {
+	dx, dy := float64(p.X-pod.X), float64(p.Y-pod.Y)
+	if dx == 0 && dy == 0 {
+		return "at the pod"
+	}
+	sector := int(math.Round(math.Atan2(dy, dx)/(math.Pi/4)) + 8)
+	return podDirections[sector%8]
+}
+
+// formatPodDistance describes where p is relative to the pod, for example
+// "42.4 tiles down left (60 walking)".
+func formatPodDistance(pod, p Point) string {
+	d := strings.TrimSuffix(fmt.Sprintf("%.1f", tileDistance(pod, p)), ".0")
+	return fmt.Sprintf("%s tiles %s (%d walking)", d, podDirection(pod, p), manhattanDistance(pod, p))
+}
+
+// geyserInfo returns the text shown for a geyser in the info panel and the
+// geyser list, including the distance from the Printing Pod when known.
+func (g *Game) geyserInfo(gy Geyser) string {
+	info := displayGeyser(gy.ID)
+	if pod, ok := findPrintingPod(g.pois); ok {
+		info += "\nFrom Pod: " + formatPodDistance(pod, Point{gy.X, gy.Y})
+	}
+	return info + "\n" + formatGeyserInfo(gy)
+}
+
+// drawPodLines draws faint lines from the Printing Pod to each of geysers.
+func (g *Game) drawPodLines(dst *ebiten.Image, geysers []Geyser) {
+	if !g.showPodLines || len(geysers) == 0 {
+		return
+	}
+	pod, ok := findPrintingPod(g.pois)
+	if !ok {
+		return
+	}
+	px := float32(float64(pod.X)*2*g.zoom + g.camX)
+	py := float32(float64(pod.Y)*2*g.zoom + g.camY)
+	for _, gy := range geysers {
+		x := float32(float64(gy.X)*2*g.zoom + g.camX)
+		y := float32(float64(gy.Y)*2*g.zoom + g.camY)
+		vector.StrokeLine(dst, px, py, x, y, 2, podLineColor, true)
+	}
+}
@@ -0,0 +1,22 @@
+package main
+
+import "testing"
+
+func TestPodDistance(t *testing.T) {
+	pod := Point{100, 100}
+	tests := []struct {
+		p    Point
+		want string
+	}{
+		{Point{130, 140}, "50 tiles down right (70 walking)"},
+		{Point{100, 60}, "40 tiles up (40 walking)"},
+		{Point{90, 90}, "14.1 tiles up left (20 walking)"},
+		{Point{40, 105}, "60.2 tiles left (65 walking)"},
+		{Point{100, 100}, "0 tiles at the pod (0 walking)"},
+	}
+	for _, tt := range tests {
+		if got := formatPodDistance(pod, tt.p); got != tt.want {
+			t.Errorf("formatPodDistance(%v) = %q, want %q", tt.p, got, tt.want)
+		}
+	}
+}
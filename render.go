@@ -0,0 +1,310 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"strings"
+	"sync"
+
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+// renderOptions controls the software renderer used for headless output.
+type renderOptions struct {
+	// Scale is the number of output pixels per world cell.
+	Scale    float64
+	Textures bool
+	Icons    bool
+	Labels   bool
+	// Legend lists the biomes and item types beside the map. Only SVG
+	// output has a legend.
+	Legend bool
+	// Title is drawn in the top-left corner when non-empty.
+	Title string
+}
+
+func defaultRenderOptions() renderOptions {
+	return renderOptions{
+		Scale:    RenderScale,
+		Textures: true,
+		Icons:    true,
+		Labels:   true,
+		Legend:   true,
+	}
+}
+
+// renderAsteroid rasterizes ast into a new image using only the CPU, so it
+// works without a window or GPU. The output mirrors what the viewer draws for
+// biomes, geysers and POIs at the given scale.
+func renderAsteroid(ast Asteroid, opts renderOptions) *image.RGBA {
+	if opts.Scale <= 0 {
+		opts.Scale = RenderScale
+	}
+	w := int(math.Ceil(float64(ast.SizeX) * opts.Scale))
+	h := int(math.Ceil(float64(ast.SizeY) * opts.Scale))
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(backgroundColor), image.Point{}, draw.Src)
+	r := &softRenderer{dst: dst, opts: opts}
+
+	space := [][]Point{{{0, 0}, {ast.SizeX, 0}, {ast.SizeX, ast.SizeY}, {0, ast.SizeY}}}
+	r.fillBiome(space, "Space")
+	for _, bp := range ast.BiomePaths.Paths {
+		r.fillBiome(bp.Polygons, bp.Name)
+	}
+	for _, bp := range ast.BiomePaths.Paths {
+		r.strokeBiome(bp.Polygons, colorWhite)
+	}
+
+	type item struct {
+		x, y int
+		icon string
+		name string
+	}
+	var items []item
+	for _, gy := range ast.Geysers {
+		items = append(items, item{gy.X, gy.Y, iconForGeyser(gy.ID), displayGeyser(gy.ID)})
+	}
+	for _, p := range ast.POIs {
+		items = append(items, item{p.X, p.Y, iconForPOI(p.ID), displayPOI(p.ID)})
+	}
+	iconSize := r.iconSize()
+	if opts.Icons {
+		for _, it := range items {
+			r.drawIcon(it.icon, r.px(it.x), r.px(it.y), iconSize)
+		}
+	}
+	if opts.Labels {
+		face := renderFace(r.fontSize())
+		defer face.Close()
+		for _, it := range items {
+			text, _ := formatLabel(it.name)
+			r.drawLabel(face, text, r.px(it.x), r.px(it.y)+iconSize/2, true)
+		}
+		if opts.Title != "" {
+			pad := int(opts.Scale * 2)
+			r.drawLabel(face, opts.Title, pad, pad, false)
+		}
+	}
+	return dst
+}
+
+// softRenderer draws into an RGBA image with golang.org/x/image/vector.
+type softRenderer struct {
+	dst  *image.RGBA
+	opts renderOptions
+}
+
+func (r *softRenderer) px(v int) int {
+	return int(math.Round(float64(v) * r.opts.Scale))
+}
+
+// iconSize matches the on-screen icon size relative to the map, where a cell
+// is two pixels at zoom 1.
+func (r *softRenderer) iconSize() int {
+	return int(math.Round(IconScale * BaseIconPixels * r.opts.Scale / 2))
+}
+
+func (r *softRenderer) fontSize() float64 {
+	return math.Max(baseFontSize, baseFontSize*r.opts.Scale/2)
+}
+
+// polyBounds returns the pixel bounds of polys clipped to the output.
+func (r *softRenderer) polyBounds(polys [][]Point, pad float64) image.Rectangle {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, pts := range polys {
+		for _, p := range pts {
+			x, y := float64(p.X)*r.opts.Scale, float64(p.Y)*r.opts.Scale
+			minX, maxX = math.Min(minX, x), math.Max(maxX, x)
+			minY, maxY = math.Min(minY, y), math.Max(maxY, y)
+		}
+	}
+	if minX > maxX {
+		return image.Rectangle{}
+	}
+	b := image.Rect(int(math.Floor(minX-pad)), int(math.Floor(minY-pad)), int(math.Ceil(maxX+pad)), int(math.Ceil(maxY+pad)))
+	return b.Intersect(r.dst.Bounds())
+}
+
+func (r *softRenderer) fillBiome(polys [][]Point, name string) {
+	b := r.polyBounds(polys, 0)
+	if b.Empty() {
+		return
+	}
+	z := vector.NewRasterizer(b.Dx(), b.Dy())
+	ox, oy := float32(b.Min.X), float32(b.Min.Y)
+	s := float32(r.opts.Scale)
+	for _, pts := range polys {
+		if len(pts) < 3 {
+			continue
+		}
+		z.MoveTo(float32(pts[0].X)*s-ox, float32(pts[0].Y)*s-oy)
+		for _, p := range pts[1:] {
+			z.LineTo(float32(p.X)*s-ox, float32(p.Y)*s-oy)
+		}
+		z.ClosePath()
+	}
+	clr, ok := biomeColors[name]
+	if !ok {
+		clr = color.RGBA{60, 60, 60, 255}
+	}
+	var src image.Image = image.NewUniform(clr)
+	if r.opts.Textures {
+		if tex := renderAsset("../biomes/" + name + ".png"); tex != nil {
+			src = biomePattern{tex: tex, clr: clr, scale: r.opts.Scale}
+		}
+	}
+	z.Draw(r.dst, b, src, b.Min)
+}
+
+// strokeBiome outlines polys with one pixel wide lines. Every edge is drawn
+// as a quad with the same winding so overlapping edges don't cancel out.
+func (r *softRenderer) strokeBiome(polys [][]Point, clr color.Color) {
+	b := r.polyBounds(polys, 1)
+	if b.Empty() {
+		return
+	}
+	z := vector.NewRasterizer(b.Dx(), b.Dy())
+	ox, oy := float64(b.Min.X), float64(b.Min.Y)
+	s := r.opts.Scale
+	for _, pts := range polys {
+		if len(pts) < 2 {
+			continue
+		}
+		for i := range pts {
+			a, c := pts[i], pts[(i+1)%len(pts)]
+			x0, y0 := float64(a.X)*s-ox, float64(a.Y)*s-oy
+			x1, y1 := float64(c.X)*s-ox, float64(c.Y)*s-oy
+			dx, dy := x1-x0, y1-y0
+			l := math.Hypot(dx, dy)
+			if l == 0 {
+				continue
+			}
+			nx, ny := -dy/l*0.5, dx/l*0.5
+			z.MoveTo(float32(x0+nx), float32(y0+ny))
+			z.LineTo(float32(x1+nx), float32(y1+ny))
+			z.LineTo(float32(x1-nx), float32(y1-ny))
+			z.LineTo(float32(x0-nx), float32(y0-ny))
+			z.ClosePath()
+		}
+	}
+	z.Draw(r.dst, b, image.NewUniform(clr), b.Min)
+}
+
+func (r *softRenderer) drawIcon(name string, cx, cy, size int) {
+	if name == "" || size <= 0 {
+		return
+	}
+	img := renderAsset(name)
+	if img == nil {
+		return
+	}
+	ib := img.Bounds()
+	scale := float64(size) / math.Max(float64(ib.Dx()), float64(ib.Dy()))
+	w := int(math.Round(float64(ib.Dx()) * scale))
+	h := int(math.Round(float64(ib.Dy()) * scale))
+	rect := image.Rect(cx-w/2, cy-h/2, cx-w/2+w, cy-h/2+h)
+	xdraw.ApproxBiLinear.Scale(r.dst, rect, img, ib, xdraw.Over, nil)
+}
+
+// drawLabel draws text on a translucent background like drawTextWithBG. When
+// center is true x is the horizontal center of the text.
+func (r *softRenderer) drawLabel(face font.Face, text string, x, y int, center bool) {
+	lines := strings.Split(text, "\n")
+	m := face.Metrics()
+	lineH := m.Height.Ceil()
+	w := 0
+	for _, l := range lines {
+		w = max(w, font.MeasureString(face, l).Ceil())
+	}
+	pad := 2
+	left := x
+	if center {
+		left = x - w/2
+	}
+	bg := image.Rect(left-pad, y-pad, left+w+pad, y+lineH*len(lines)+pad)
+	draw.Draw(r.dst, bg, image.NewUniform(legendBGColor), image.Point{}, draw.Over)
+	d := &font.Drawer{Dst: r.dst, Src: image.White, Face: face}
+	for i, l := range lines {
+		lx := left
+		if center {
+			lx = x - font.MeasureString(face, l).Ceil()/2
+		}
+		d.Dot = fixed.P(lx, y+i*lineH+m.Ascent.Ceil())
+		d.DrawString(l)
+	}
+}
+
+// biomePattern tiles a biome texture over the output tinted by clr, the same
+// way drawBiomeTextured repeats textures on the GPU.
+type biomePattern struct {
+	tex   *image.NRGBA
+	clr   color.RGBA
+	scale float64
+}
+
+func (p biomePattern) ColorModel() color.Model { return color.RGBAModel }
+
+func (p biomePattern) Bounds() image.Rectangle {
+	return image.Rect(math.MinInt32/2, math.MinInt32/2, math.MaxInt32/2, math.MaxInt32/2)
+}
+
+func (p biomePattern) At(x, y int) color.Color {
+	b := p.tex.Bounds()
+	tw, th := b.Dx(), b.Dy()
+	u := (float64(x) + 0.5) / p.scale * BiomeTextureScale * float64(tw)
+	v := (float64(y) + 0.5) / p.scale * BiomeTextureScale * float64(th)
+	tx := ((int(math.Floor(u)) % tw) + tw) % tw
+	ty := ((int(math.Floor(v)) % th) + th) % th
+	c := p.tex.NRGBAAt(b.Min.X+tx, b.Min.Y+ty)
+	a := uint32(c.A) * uint32(p.clr.A) / 255
+	return color.RGBA{
+		R: uint8(uint32(c.R) * uint32(p.clr.R) / 255 * a / 255),
+		G: uint8(uint32(c.G) * uint32(p.clr.G) / 255 * a / 255),
+		B: uint8(uint32(c.B) * uint32(p.clr.B) / 255 * a / 255),
+		A: uint8(a),
+	}
+}
+
+var (
+	renderAssetsMu sync.Mutex
+	renderAssets   = map[string]*image.NRGBA{}
+)
+
+// renderAsset returns a decoded embedded image, caching it for later renders.
+// Missing assets are cached as nil.
+func renderAsset(name string) *image.NRGBA {
+	renderAssetsMu.Lock()
+	defer renderAssetsMu.Unlock()
+	if img, ok := renderAssets[name]; ok {
+		return img
+	}
+	img, err := decodeAssetImage(name)
+	if err != nil {
+		img = nil
+	}
+	renderAssets[name] = img
+	return img
+}
+
+// renderFace returns a font face at size pixels for the software renderer.
+func renderFace(size float64) font.Face {
+	if fontParsed == nil {
+		var err error
+		fontParsed, err = opentype.Parse(notoTTF)
+		if err != nil {
+			panic("failed to parse font: " + err.Error())
+		}
+	}
+	face, err := opentype.NewFace(fontParsed, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		panic("failed to create font face: " + err.Error())
+	}
+	return face
+}
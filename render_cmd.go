@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// runRender implements the "render" subcommand which writes map images for
+// one or more seeds without opening a window.
+func runRender(args []string) int {
+	fs := newCommandFlags("render", "render [flags] COORD|FILE.pb[.gz]...")
+	src := addSeedSourceFlags(fs)
+	asteroids := fs.String("asteroid", "", "comma separated asteroid IDs to render, or \"all\" (default: the starting asteroid)")
+	outDir := fs.String("out", ".", "directory for rendered images")
+	output := fs.String("o", "", "output file name when rendering a single image")
+	opts := defaultRenderOptions()
+	fs.Float64Var(&opts.Scale, "scale", opts.Scale, "output pixels per world cell")
+	fs.BoolVar(&opts.Textures, "textures", opts.Textures, "draw biome textures")
+	fs.BoolVar(&opts.Icons, "icons", opts.Icons, "draw geyser and POI icons")
+	fs.BoolVar(&opts.Labels, "labels", opts.Labels, "draw geyser and POI names")
+	fs.BoolVar(&opts.Legend, "legend", opts.Legend, "add a biome and item legend to SVG output")
+	format := fs.String("format", "", "image format: png, jpeg, webp or svg (default: from the -o extension, else png)")
+	quality := fs.Int("quality", RenderJPEGQuality, "JPEG quality (1-100)")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	src.apply()
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+	if opts.Scale <= 0 {
+		commandError(os.Stderr, "render", fmt.Errorf("-scale must be positive"))
+		return 2
+	}
+	enc, err := outputEncoding(*format, *output, *quality)
+	if err != nil {
+		commandError(os.Stderr, "render", err)
+		return 2
+	}
+	ids := splitList(*asteroids)
+	if *output != "" && (fs.NArg() > 1 || len(ids) > 1 || strings.EqualFold(*asteroids, "all")) {
+		commandError(os.Stderr, "render", fmt.Errorf("-o can only be used for a single image; use -out for batches"))
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	failed := false
+	for _, arg := range fs.Args() {
+		if ctx.Err() != nil {
+			return 1
+		}
+		s := parseSeedSource(arg)
+		paths, err := renderSeed(ctx, s, ids, opts, enc, *outDir, *output)
+		for _, p := range paths {
+			fmt.Println(p)
+		}
+		if err != nil {
+			commandError(os.Stderr, "render", fmt.Errorf("%s: %v", arg, err))
+			failed = true
+		}
+	}
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+// renderSeed renders the selected asteroids of one seed and returns the paths
+// written. output overrides the generated file name for a single image.
+func renderSeed(ctx context.Context, src seedSource, ids []string, opts renderOptions, enc imageEncoding, outDir, output string) ([]string, error) {
+	seed, err := loadSeed(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+	asts, err := selectAsteroids(seed, ids)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, a := range asts {
+		o := opts
+		if o.Title == "" && o.Labels {
+			o.Title = src.coord + " - " + a.ID
+		}
+		path := output
+		if path == "" {
+			path = filepath.Join(outDir, renderFileName(src.coord, a.ID, enc.Format))
+		}
+		if enc.Format == formatSVG {
+			if err := writeSVGFile(path, a, svgOptions{renderOptions: o}); err != nil {
+				return paths, err
+			}
+			paths = append(paths, path)
+			continue
+		}
+		img := renderAsteroid(a, o)
+		meta := imageMetadata{Coord: src.coord, Asteroid: a.ID, Time: time.Now()}
+		if err := writeImageFile(path, img, enc, meta); err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// renderScreenshot handles the viewer's -screenshot flag by rendering the
+// requested asteroid headlessly.
+func renderScreenshot(coord, file, asteroidID, path string) int {
+	var ids []string
+	if asteroidID != "" {
+		ids = []string{asteroidID}
+	}
+	enc, err := outputEncoding("", path, RenderJPEGQuality)
+	if err != nil {
+		commandError(os.Stderr, "screenshot", err)
+		return 2
+	}
+	src := seedSource{coord: coord, file: file}
+	if _, err := renderSeed(context.Background(), src, ids, defaultRenderOptions(), enc, "", path); err != nil {
+		commandError(os.Stderr, "screenshot", err)
+		return 1
+	}
+	return 0
+}
+
+// outputEncoding picks the image format from the -format flag, falling back
+// to the extension of output and then PNG.
+func outputEncoding(format, output string, quality int) (imageEncoding, error) {
+	enc := imageEncoding{Format: formatPNG, JPEGQuality: quality}
+	if quality < 1 || quality > 100 {
+		return enc, fmt.Errorf("JPEG quality %d is outside 1-100", quality)
+	}
+	var err error
+	switch {
+	case format != "":
+		enc.Format, err = parseImageFormat(format)
+	case filepath.Ext(output) != "":
+		enc.Format, err = parseImageFormat(filepath.Ext(output))
+	}
+	return enc, err
+}
+
+// renderFileName builds a file name such as
+// "V-FRST-C-1331877-0-0-0-Vanilla_Verdante.png".
+func renderFileName(coord, asteroidID string, format imageFormat) string {
+	return sanitizeFileName(coord+"-"+asteroidID) + format.ext()
+}
+
+// sanitizeFileName replaces characters that are awkward in file names.
+func sanitizeFileName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '.':
+			return r
+		}
+		return '_'
+	}, s)
+}
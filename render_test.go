@@ -0,0 +1,38 @@
+package main
+
+import (
+	"image/color"
+	"testing"
+)
+
+// TestRenderAsteroid verifies the software renderer fills biomes with their
+// colors at the requested scale.
+func TestRenderAsteroid(t *testing.T) {
+	ast := Asteroid{
+		ID:    "Terra",
+		SizeX: 20,
+		SizeY: 10,
+		BiomePaths: BiomePathsCompact{Paths: []BiomePath{{
+			Name:     "Sandstone",
+			Polygons: [][]Point{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}},
+		}}},
+		Geysers: []Geyser{{ID: "steam", X: 5, Y: 5}},
+	}
+	opts := renderOptions{Scale: 2}
+	img := renderAsteroid(ast, opts)
+	if b := img.Bounds(); b.Dx() != 40 || b.Dy() != 20 {
+		t.Fatalf("unexpected size %v", b)
+	}
+	if got, want := img.RGBAAt(5, 10), biomeColors["Sandstone"]; got != want {
+		t.Fatalf("sandstone pixel = %v, want %v", got, want)
+	}
+	if got, want := img.RGBAAt(35, 10), biomeColors["Space"]; got != want {
+		t.Fatalf("space pixel = %v, want %v", got, want)
+	}
+
+	// Icons, labels and textures must render without a window.
+	img = renderAsteroid(ast, defaultRenderOptions())
+	if img.RGBAAt(0, 0) == (color.RGBA{}) {
+		t.Fatal("expected a drawn pixel")
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+)
+
+// runScore implements the "score" subcommand which checks seeds against a
+// rule set and prints the per-rule results.
+func runScore(args []string) int {
+	fs := newCommandFlags("score", "score [flags] COORD|FILE.pb[.gz]...")
+	src := addSeedSourceFlags(fs)
+	rulesPath := fs.String("rules", "", "YAML or JSON rule file (default: the built-in checklist)")
+	asteroids := fs.String("asteroid", "", "comma separated asteroid IDs to score, or \"all\" (default: the starting asteroid)")
+	asJSON := fs.Bool("json", false, "print results as JSON lines")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	src.apply()
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+	rules, err := loadScoreRules(*rulesPath)
+	if err != nil {
+		commandError(os.Stderr, "score", err)
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ids := splitList(*asteroids)
+	failed := false
+	for _, arg := range fs.Args() {
+		if ctx.Err() != nil {
+			return 1
+		}
+		s := parseSeedSource(arg)
+		if err := scoreSeed(ctx, os.Stdout, s, rules, ids, *asJSON); err != nil {
+			commandError(os.Stderr, "score", fmt.Errorf("%s: %v", arg, err))
+			failed = true
+		}
+	}
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+// scoreSeed evaluates rules for the selected asteroids of one seed and writes
+// a report for each.
+func scoreSeed(ctx context.Context, w io.Writer, src seedSource, rules scoreRuleSet, ids []string, asJSON bool) error {
+	seed, err := loadSeed(ctx, src)
+	if err != nil {
+		return err
+	}
+	asts, err := selectAsteroids(seed, ids)
+	if err != nil {
+		return err
+	}
+	for _, a := range asts {
+		rep := rules.evaluate(seed.Asteroids, a)
+		if asJSON {
+			err = writeScoreJSON(w, src.coord, rep)
+		} else {
+			err = writeScoreText(w, src.coord, rep)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeScoreText(w io.Writer, coord string, rep scoreReport) error {
+	if _, err := fmt.Fprintf(w, "%s %s: %s\n", coord, rep.Asteroid, rep.summary()); err != nil {
+		return err
+	}
+	for _, res := range rep.Results {
+		if _, err := fmt.Fprintf(w, "  %s\n", res.label()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scoreJSON is one line of -json output.
+type scoreJSON struct {
+	Coordinate string           `json:"coordinate"`
+	Asteroid   string           `json:"asteroid"`
+	RuleSet    string           `json:"ruleSet"`
+	Score      int              `json:"score"`
+	MaxScore   int              `json:"maxScore"`
+	Rules      []ruleResultJSON `json:"rules"`
+}
+
+type ruleResultJSON struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Count  int    `json:"count"`
+	Points int    `json:"points"`
+}
+
+func writeScoreJSON(w io.Writer, coord string, rep scoreReport) error {
+	out := scoreJSON{
+		Coordinate: coord,
+		Asteroid:   rep.Asteroid,
+		RuleSet:    rep.RuleSet,
+		Score:      rep.Score,
+		MaxScore:   rep.MaxScore,
+	}
+	for _, res := range rep.Results {
+		out.Rules = append(out.Rules, ruleResultJSON{res.Rule.Name, res.Passed, res.Count, res.Rule.points()})
+	}
+	return json.NewEncoder(w).Encode(out)
+}
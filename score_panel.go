@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// scoreRulesPath is the rule file used by the score panel. Empty selects the
+// built-in checklist.
+var scoreRulesPath string
+
+// openScorePanel evaluates the score rules for the current asteroid and shows
+// the results.
+func (g *Game) openScorePanel() {
+	g.closeMenus()
+	g.scoreReport = nil
+	g.scoreErr = ""
+	g.scoreScroll = 0
+	rules, err := loadScoreRules(scoreRulesPath)
+	idx := asteroidIndexByID(g.asteroids, g.asteroidID)
+	switch {
+	case err != nil:
+		g.scoreErr = err.Error()
+	case idx < 0:
+		g.scoreErr = "no asteroid loaded"
+	default:
+		rep := rules.evaluate(g.asteroids, g.asteroids[idx])
+		g.scoreReport = &rep
+	}
+	g.showScore = true
+	g.needsRedraw = true
+}
+
+func (g *Game) closeScorePanel() {
+	g.showScore = false
+	g.needsRedraw = true
+}
+
+// scoreLines returns the text rows of the panel and the border color of
+// each.
+func (g *Game) scoreLines() ([]string, []color.Color) {
+	if g.scoreReport == nil {
+		return []string{g.scoreErr}, []color.Color{failColor}
+	}
+	rep := g.scoreReport
+	lines := []string{fmt.Sprintf("%s %s - %s", ScoreTitle, rep.summary(), truncateString(rep.Asteroid, 32))}
+	colors := []color.Color{buttonBorderColor}
+	for _, res := range rep.Results {
+		lines = append(lines, res.label())
+		if res.Passed {
+			colors = append(colors, passColor)
+		} else {
+			colors = append(colors, failColor)
+		}
+	}
+	return lines, colors
+}
+
+func (g *Game) maxScoreScroll() float64 {
+	lines, _ := g.scoreLines()
+	h := (len(lines)+1)*rowSpacing() + uiScaled(ClusterMapMargin)*2
+	return max(0, float64(h-g.height))
+}
+
+// handleScoreInput toggles the panel with the R key and handles scrolling and
+// closing. It returns true while the panel is shown.
+func (g *Game) handleScoreInput() bool {
+	if inpututil.IsKeyJustPressed(ebiten.KeyR) && len(g.asteroids) > 0 && !g.loading && !g.showSeedInput && !g.textInputActive() {
+		if g.showScore {
+			g.closeScorePanel()
+		} else {
+			g.openScorePanel()
+		}
+		return true
+	}
+	if !g.showScore {
+		return false
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		g.closeScorePanel()
+		return true
+	}
+	if _, wheelY := ebiten.Wheel(); wheelY != 0 {
+		g.scoreScroll = min(max(0, g.scoreScroll-wheelY*10), g.maxScoreScroll())
+		g.needsRedraw = true
+	}
+	var clicks []image.Point
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		mx, my := ebiten.CursorPosition()
+		clicks = append(clicks, image.Pt(mx, my))
+	}
+	for _, id := range inpututil.AppendJustPressedTouchIDs(nil) {
+		x, y := ebiten.TouchPosition(id)
+		clicks = append(clicks, image.Pt(x, y))
+	}
+	for _, pt := range clicks {
+		if pt.In(g.geyserCloseRect()) {
+			g.closeScorePanel()
+		}
+	}
+	return true
+}
+
+// drawScoreScreen draws the score results in place of the map view.
+func (g *Game) drawScoreScreen(dst *ebiten.Image) bool {
+	if !g.showScore {
+		return false
+	}
+	dst.Fill(backgroundColor)
+	margin := uiScaled(ClusterMapMargin)
+	y := margin - int(g.scoreScroll)
+	lines, colors := g.scoreLines()
+	for i, l := range lines {
+		drawTextWithBGBorder(dst, l, margin, y, colors[i], false)
+		y += rowSpacing()
+		if i == 0 {
+			y += rowSpacing() / 2
+		}
+	}
+	drawCloseButton(dst, g.geyserCloseRect())
+	g.needsRedraw = false
+	g.lastDraw = time.Now()
+	return true
+}
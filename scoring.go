@@ -0,0 +1,320 @@
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed data/score_rules.yaml
+var defaultScoreRulesYAML []byte
+
+// scoreRuleSet is a named checklist of rules loaded from YAML or JSON.
+type scoreRuleSet struct {
+	Name  string      `yaml:"name"`
+	Rules []scoreRule `yaml:"rules"`
+}
+
+// scoreRule counts the geysers, POIs or world traits matching its filters
+// and passes when the count is within [Min, Max]. A rule without bounds
+// requires at least one match.
+type scoreRule struct {
+	Name string `yaml:"name"`
+	// Scope is "asteroid" to check the selected asteroid or "cluster" to
+	// count across every asteroid of the seed.
+	Scope string `yaml:"scope"`
+	// Geysers or POIs lists the IDs or display names to count; "*" matches
+	// any.
+	Geysers []string `yaml:"geysers"`
+	POIs    []string `yaml:"pois"`
+	// Traits lists world trait IDs or names such as "MetalPoor".
+	Traits []string `yaml:"traits"`
+	Min    *int     `yaml:"min"`
+	Max    *int     `yaml:"max"`
+	// Within only counts items at most this many tiles from the Printing
+	// Pod of their asteroid.
+	Within float64 `yaml:"within"`
+	// Where filters geysers by their stats.
+	Where  []statCondition `yaml:"where"`
+	Points *int            `yaml:"points"`
+}
+
+// statCondition bounds one geyser stat, see geyserStats.
+type statCondition struct {
+	Stat string   `yaml:"stat"`
+	Min  *float64 `yaml:"min"`
+	Max  *float64 `yaml:"max"`
+}
+
+const (
+	scopeAsteroid = "asteroid"
+	scopeCluster  = "cluster"
+)
+
+// geyserStats are the stats rules can filter on. Names match the JSON
+// export.
+var geyserStats = map[string]func(Geyser) float64{
+	"emitRate":        func(g Geyser) float64 { return g.EmitRate },
+	"avgEmitRate":     func(g Geyser) float64 { return g.AvgEmitRate },
+	"eruptionTime":    func(g Geyser) float64 { return g.EruptionTime },
+	"idleTime":        func(g Geyser) float64 { return g.IdleTime },
+	"activeCycles":    func(g Geyser) float64 { return g.ActiveCycles },
+	"dormancyCycles":  func(g Geyser) float64 { return g.DormancyCycles },
+	"effectiveOutput": Geyser.effectiveOutput,
+	"outputPerCycle":  Geyser.effectiveOutputPerCycle,
+	// quality is the percentile from 0 to 100, or NaN for unknown types.
+	"quality": func(g Geyser) float64 {
+		if q, ok := geyserQuality(g); ok {
+			return q * 100
+		}
+		return math.NaN()
+	},
+	// temperature is the output temperature in degrees Celsius.
+	"temperature": func(g Geyser) float64 {
+		if t, ok := lookupGeyserType(g.ID); ok {
+			return t.Temperature - 273.15
+		}
+		return math.NaN()
+	},
+}
+
+// parseScoreRules decodes a rule set from YAML or JSON and validates it.
+// Unknown keys are rejected so a misspelled constraint is not silently
+// dropped.
+func parseScoreRules(data []byte) (scoreRuleSet, error) {
+	var rs scoreRuleSet
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&rs); err != nil && !errors.Is(err, io.EOF) {
+		return rs, err
+	}
+	if len(rs.Rules) == 0 {
+		return rs, errors.New("no rules defined")
+	}
+	for i := range rs.Rules {
+		r := &rs.Rules[i]
+		if r.Name == "" {
+			r.Name = fmt.Sprintf("Rule %d", i+1)
+		}
+		if err := r.validate(); err != nil {
+			return rs, fmt.Errorf("%s: %w", r.Name, err)
+		}
+	}
+	return rs, nil
+}
+
+func (r *scoreRule) validate() error {
+	switch r.Scope {
+	case "":
+		r.Scope = scopeAsteroid
+	case scopeAsteroid, scopeCluster:
+	default:
+		return fmt.Errorf("unknown scope %q (use asteroid or cluster)", r.Scope)
+	}
+	set := 0
+	for _, l := range [][]string{r.Geysers, r.POIs, r.Traits} {
+		if len(l) > 0 {
+			set++
+		}
+	}
+	if set != 1 {
+		return errors.New("set exactly one of geysers, pois or traits")
+	}
+	if len(r.Where) > 0 && len(r.Geysers) == 0 {
+		return errors.New("where conditions only apply to geysers")
+	}
+	for _, c := range r.Where {
+		if _, ok := geyserStats[c.Stat]; !ok {
+			return fmt.Errorf("unknown stat %q", c.Stat)
+		}
+		if c.Min == nil && c.Max == nil {
+			return fmt.Errorf("stat %s needs a min or max", c.Stat)
+		}
+	}
+	if r.Min != nil && r.Max != nil && *r.Min > *r.Max {
+		return errors.New("min is greater than max")
+	}
+	if r.Within < 0 {
+		return errors.New("within must not be negative")
+	}
+	return nil
+}
+
+// loadScoreRules reads a rule set from path, or returns the built-in rules
+// when path is empty.
+func loadScoreRules(path string) (scoreRuleSet, error) {
+	if path == "" {
+		return parseScoreRules(defaultScoreRulesYAML)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return scoreRuleSet{}, err
+	}
+	rs, err := parseScoreRules(data)
+	if err != nil {
+		return rs, fmt.Errorf("%s: %w", path, err)
+	}
+	return rs, nil
+}
+
+func (r scoreRule) points() int {
+	if r.Points == nil {
+		return 1
+	}
+	return *r.Points
+}
+
+// bounds returns the accepted count range; max is -1 when unbounded.
+func (r scoreRule) bounds() (lo, hi int) {
+	lo, hi = 1, -1
+	if r.Min != nil || r.Max != nil {
+		lo = 0
+	}
+	if r.Min != nil {
+		lo = *r.Min
+	}
+	if r.Max != nil {
+		hi = *r.Max
+	}
+	return lo, hi
+}
+
+// matchesID reports whether id is named by list, by game ID or display name.
+func matchesID(list []string, id, name string) bool {
+	id = simplifyID(id)
+	for _, s := range list {
+		if s == "*" || strings.EqualFold(s, id) || strings.EqualFold(s, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// count returns how many items of a match the rule's filters.
+func (r scoreRule) count(a Asteroid) int {
+	pod, hasPod := printingPod(a)
+	near := func(x, y int) bool {
+		if r.Within <= 0 {
+			return true
+		}
+		return hasPod && tileDistance(pod, Point{x, y}) <= r.Within
+	}
+	n := 0
+	for _, gy := range a.Geysers {
+		if !matchesID(r.Geysers, gy.ID, names.Geysers[simplifyID(gy.ID)]) || !near(gy.X, gy.Y) {
+			continue
+		}
+		if r.statsMatch(gy) {
+			n++
+		}
+	}
+	for _, p := range a.POIs {
+		if matchesID(r.POIs, p.ID, names.POIs[simplifyID(p.ID)]) && near(p.X, p.Y) {
+			n++
+		}
+	}
+	for _, t := range a.Traits {
+		if matchesID(r.Traits, t, worldTraitName(t)) {
+			n++
+		}
+	}
+	return n
+}
+
+func (r scoreRule) statsMatch(g Geyser) bool {
+	for _, c := range r.Where {
+		v := geyserStats[c.Stat](g)
+		if math.IsNaN(v) || (c.Min != nil && v < *c.Min) || (c.Max != nil && v > *c.Max) {
+			return false
+		}
+	}
+	return true
+}
+
+// ruleResult is the outcome of one rule.
+type ruleResult struct {
+	Rule   scoreRule
+	Count  int
+	Passed bool
+}
+
+// detail describes the count against the rule's bounds, e.g.
+// "3 found, need at least 2".
+func (res ruleResult) detail() string {
+	lo, hi := res.Rule.bounds()
+	var need string
+	switch {
+	case hi < 0:
+		need = fmt.Sprintf("at least %d", lo)
+	case lo == 0 && hi == 0:
+		need = "none"
+	case lo == 0:
+		need = fmt.Sprintf("at most %d", hi)
+	case lo == hi:
+		need = fmt.Sprintf("exactly %d", lo)
+	default:
+		need = fmt.Sprintf("%d-%d", lo, hi)
+	}
+	return fmt.Sprintf("%d found, need %s", res.Count, need)
+}
+
+// scoreReport is the result of checking a rule set against one asteroid and
+// its cluster.
+type scoreReport struct {
+	RuleSet  string
+	Asteroid string
+	Results  []ruleResult
+	Score    int
+	MaxScore int
+}
+
+// evaluate checks rs against target, with cluster scoped rules counting over
+// every asteroid in asts.
+func (rs scoreRuleSet) evaluate(asts []Asteroid, target Asteroid) scoreReport {
+	rep := scoreReport{RuleSet: rs.Name, Asteroid: target.ID}
+	for _, r := range rs.Rules {
+		n := 0
+		if r.Scope == scopeCluster {
+			for _, a := range asts {
+				n += r.count(a)
+			}
+		} else {
+			n = r.count(target)
+		}
+		lo, hi := r.bounds()
+		res := ruleResult{Rule: r, Count: n, Passed: n >= lo && (hi < 0 || n <= hi)}
+		rep.Results = append(rep.Results, res)
+		if pts := r.points(); pts > 0 {
+			rep.MaxScore += pts
+		}
+		if res.Passed {
+			rep.Score += r.points()
+		}
+	}
+	return rep
+}
+
+// summary returns a line such as "7/10 Default checklist".
+func (rep scoreReport) summary() string {
+	s := fmt.Sprintf("%d/%d", rep.Score, rep.MaxScore)
+	if rep.RuleSet != "" {
+		s += " " + rep.RuleSet
+	}
+	return s
+}
+
+// label formats one result for text output.
+func (res ruleResult) label() string {
+	status := "FAIL"
+	if res.Passed {
+		status = "PASS"
+	}
+	return fmt.Sprintf("%s  %s (%s)", status, res.Rule.Name, res.detail())
+}
@@ -0,0 +1,81 @@
+package main
+
+import "testing"
+
+func TestScoreRules(t *testing.T) {
+	rules, err := parseScoreRules([]byte(`
+name: Test
+rules:
+  - name: Two water sources
+    geysers: [hot_water, Salt Water Geyser]
+    min: 2
+    points: 3
+  - name: Metal volcano near the pod
+    geysers: [molten_iron]
+    within: 60
+  - name: No hot PO2
+    geysers: [hot_po2]
+    max: 0
+  - name: Strong geysers in the cluster
+    scope: cluster
+    geysers: ["*"]
+    where:
+      - stat: effectiveOutput
+        min: 100
+    min: 3
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	home := Asteroid{
+		ID:   "Terra",
+		POIs: []PointOfInterest{{ID: "Headquarters", X: 100, Y: 100}},
+		Geysers: []Geyser{
+			{ID: "hot_water", X: 10, Y: 10, EmitRate: 1000, EruptionTime: 1, ActiveCycles: 1},
+			{ID: "salt_water", X: 20, Y: 10, EmitRate: 50, EruptionTime: 1, ActiveCycles: 1},
+			{ID: "molten_iron", X: 200, Y: 100, EmitRate: 1000, EruptionTime: 1, ActiveCycles: 1},
+		},
+	}
+	other := Asteroid{
+		ID:      "Moon",
+		Geysers: []Geyser{{ID: "hot_po2", EmitRate: 500, EruptionTime: 1, ActiveCycles: 1}},
+	}
+	rep := rules.evaluate([]Asteroid{home, other}, home)
+	want := []bool{true, false, true, true}
+	for i, res := range rep.Results {
+		if res.Passed != want[i] {
+			t.Errorf("%s: passed = %v (%s), want %v", res.Rule.Name, res.Passed, res.detail(), want[i])
+		}
+	}
+	if rep.Score != 5 || rep.MaxScore != 6 {
+		t.Errorf("score = %d/%d, want 5/6", rep.Score, rep.MaxScore)
+	}
+	if rep = rules.evaluate([]Asteroid{home, other}, other); rep.Results[2].Passed {
+		t.Error("hot PO2 rule passed on the asteroid with the vent")
+	}
+}
+
+func TestScoreRulesJSONAndErrors(t *testing.T) {
+	if _, err := parseScoreRules([]byte(`{"rules": [{"name": "Pod", "pois": ["Headquarters"]}]}`)); err != nil {
+		t.Fatalf("JSON rules: %v", err)
+	}
+	bad := []string{
+		`rules: []`,
+		`rules: [{geysers: [steam], pois: [Headquarters]}]`,
+		`rules: [{geysers: [steam], scope: world}]`,
+		`rules: [{geysers: [steam], where: [{stat: size, min: 1}]}]`,
+		`rules: [{geysers: [steam], min: 3, max: 1}]`,
+		// Misspelled keys would otherwise drop the constraint.
+		`rules: [{geysers: [steam], mni: 3}]`,
+		`rules: [{geysers: [steam], where: [{stats: emitRate, min: 1}]}]`,
+		`{"rules": [{"pois": ["Headquarters"], "mxa": 1}]}`,
+	}
+	for _, s := range bad {
+		if _, err := parseScoreRules([]byte(s)); err == nil {
+			t.Errorf("expected error for %s", s)
+		}
+	}
+	if _, err := loadScoreRules(""); err != nil {
+		t.Fatalf("built-in rules: %v", err)
+	}
+}
@@ -6,8 +6,6 @@ import (
 	"image"
 	"time"
 
-	"golang.org/x/image/bmp"
-
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
@@ -31,12 +29,43 @@ func (g *Game) screenshotRect() image.Rectangle {
 	return image.Rect(x, y, x+size, y+size)
 }
 
+// Screenshot menu rows that follow the quality presets.
+const (
+	ssRowBW = iota
+	ssRowNotes
+	ssRowFormat
+	ssRowJPEG
+	ssRowSave
+	ssRowCancel
+)
+
+// screenshotMenuItems returns the button labels of the screenshot menu with
+// save used for the save button.
+func (g *Game) screenshotMenuItems(save string) []string {
+	items := append([]string(nil), ScreenshotQualities...)
+	return append(items,
+		ScreenshotBWLabel,
+		ScreenshotNotesLabel,
+		ScreenshotFormatLabel+g.ssFormat.String(),
+		fmt.Sprintf("%s%d", ScreenshotJPEGLabel, ScreenshotJPEGQualities[g.ssJPEGQuality]),
+		save,
+		ScreenshotCancelLabel,
+	)
+}
+
+// screenshotMenuGap reports whether extra space follows row i.
+func screenshotMenuGap(i int) bool {
+	return i == len(ScreenshotQualities)-1 || i == len(ScreenshotQualities)+ssRowJPEG
+}
+
 func (g *Game) screenshotMenuSize() (int, int) {
-	labels := append([]string{ScreenshotMenuTitle}, ScreenshotQualities...)
-	labels = append(labels, ScreenshotBWLabel, ScreenshotSaveLabel, ScreenshotCancelLabel)
+	labels := append([]string{ScreenshotMenuTitle}, g.screenshotMenuItems(ScreenshotSaveLabel)...)
 	itemCount := len(labels)
 	allLabels := append([]string(nil), labels...)
-	allLabels = append(allLabels, ScreenshotTakingLabel, ScreenshotSavedLabel)
+	allLabels = append(allLabels, ScreenshotTakingLabel, ScreenshotSavedLabel, ScreenshotFailedLabel)
+	for _, f := range imageFormatNames {
+		allLabels = append(allLabels, ScreenshotFormatLabel+f)
+	}
 	maxW := 0
 	for _, s := range allLabels {
 		w, _ := textDimensions(s)
@@ -44,8 +73,8 @@ func (g *Game) screenshotMenuSize() (int, int) {
 			maxW = w
 		}
 	}
-	w := maxW + uiScaled(4)
-	// Extra spacing after quality options and the B&W toggle
+	w := maxW + uiScaled(16)
+	// Extra spacing after quality options and the format settings
 	h := (itemCount+2)*menuSpacing() + uiScaled(6)
 	return w, h
 }
@@ -81,25 +110,35 @@ func (g *Game) drawScreenshotMenu(dst *ebiten.Image) {
 	label := ScreenshotSaveLabel
 	if g.ssPending > 0 {
 		label = ScreenshotTakingLabel
+	} else if g.ssErr != "" {
+		label = ScreenshotFailedLabel
 	} else if time.Since(g.ssSaved) < 3*time.Second {
 		label = ScreenshotSavedLabel
 	}
-	items := append([]string(nil), ScreenshotQualities...)
-	items = append(items, ScreenshotBWLabel, label, ScreenshotCancelLabel)
+	items := g.screenshotMenuItems(label)
 	y := pad + menuSpacing()
 	for i, it := range items {
 		btn := image.Rect(uiScaled(4), y-uiScaled(4), w-uiScaled(4), y-uiScaled(4)+menuButtonHeight())
 		selected := i == g.ssQuality
-		switch i {
-		case len(ScreenshotQualities):
+		switch i - len(ScreenshotQualities) {
+		case ssRowBW:
 			drawButton(img, btn, g.ssNoColor)
-		case len(ScreenshotQualities) + 1:
+		case ssRowNotes:
+			drawButton(img, btn, g.ssAnnotations)
+		case ssRowFormat:
+			drawButton(img, btn, false)
+		case ssRowJPEG:
+			drawButton(img, btn, false)
+			if g.ssFormat != formatJPEG {
+				it = ""
+			}
+		case ssRowSave:
 			if g.ssPending > 0 {
 				drawButton(img, btn, true)
 			} else {
 				drawButton(img, btn, false)
 			}
-		case len(ScreenshotQualities) + 2:
+		case ssRowCancel:
 			drawButton(img, btn, true)
 		default:
 			drawButton(img, btn, selected)
@@ -110,13 +149,17 @@ func (g *Game) drawScreenshotMenu(dst *ebiten.Image) {
 		}
 		drawText(img, it, btn.Min.X+pad, btn.Min.Y+(menuButtonHeight()-lh)/2, false)
 		y += menuSpacing()
-		if i == len(ScreenshotQualities)-1 || i == len(ScreenshotQualities) {
+		if screenshotMenuGap(i) {
 			y += menuSpacing()
 		}
 	}
 	op := &ebiten.DrawImageOptions{}
 	op.GeoM.Translate(float64(rect.Min.X), float64(rect.Min.Y))
 	dst.DrawImage(img, op)
+	if g.ssErr != "" {
+		_, th := textDimensions(g.ssErr)
+		drawTextWithBG(dst, g.ssErr, rect.Min.X+rect.Dx()/2, rect.Min.Y-th-pad, true)
+	}
 }
 
 func (g *Game) clickScreenshotMenu(mx, my int) bool {
@@ -128,39 +171,50 @@ func (g *Game) clickScreenshotMenu(mx, my int) bool {
 	y := my - rect.Min.Y
 	mx = x
 	my = y
-	items := append([]string(nil), ScreenshotQualities...)
-	items = append(items, ScreenshotBWLabel, ScreenshotSaveLabel, ScreenshotCancelLabel)
+	items := g.screenshotMenuItems(ScreenshotSaveLabel)
 	y = uiScaled(6) + menuSpacing()
 	w, _ := g.screenshotMenuSize()
 	for i := range items {
 		r := image.Rect(uiScaled(4), y-uiScaled(4), w-uiScaled(4), y-uiScaled(4)+menuButtonHeight())
 		if r.Overlaps(image.Rect(mx, my, mx+1, my+1)) {
-			switch i {
-			case 0, 1, 2:
-				g.ssQuality = i
-			case len(ScreenshotQualities):
+			switch i - len(ScreenshotQualities) {
+			case ssRowBW:
 				g.ssNoColor = !g.ssNoColor
 				g.noColor = g.ssNoColor
-			case len(ScreenshotQualities) + 1:
+			case ssRowNotes:
+				g.ssAnnotations = !g.ssAnnotations
+			case ssRowFormat:
+				g.ssFormat = (g.ssFormat + 1) % imageFormat(len(imageFormatNames))
+			case ssRowJPEG:
+				if g.ssFormat == formatJPEG {
+					g.ssJPEGQuality = (g.ssJPEGQuality + 1) % len(ScreenshotJPEGQualities)
+				}
+			case ssRowSave:
 				if g.ssPending == 0 {
 					g.ssPending = 2
 				}
-			case len(ScreenshotQualities) + 2:
+			case ssRowCancel:
 				g.showShotMenu = false
 				g.noColor = false
+				g.ssErr = ""
+			default:
+				g.ssQuality = i
 			}
 			g.needsRedraw = true
 			return true
 		}
 		y += menuSpacing()
-		if i == len(ScreenshotQualities)-1 || i == len(ScreenshotQualities) {
+		if screenshotMenuGap(i) {
 			y += menuSpacing()
 		}
 	}
 	return false
 }
 
-func (g *Game) saveScreenshot() {
+func (g *Game) saveScreenshot() error {
+	if g.ssFormat == formatSVG {
+		return g.saveSVG()
+	}
 	scale := ScreenshotScales[g.ssQuality]
 	width := int(float64(g.astWidth) * 2 * scale)
 	height := int(float64(g.astHeight) * 2 * scale)
@@ -168,10 +222,14 @@ func (g *Game) saveScreenshot() {
 	if g.ssNoColor {
 		desaturateImage(img)
 	}
+	now := time.Now()
+	meta := imageMetadata{Coord: g.coord, Asteroid: g.asteroidID, Time: now}
 	var buf bytes.Buffer
-	_ = bmp.Encode(&buf, img)
-	name := fmt.Sprintf("%s-%s.bmp", g.coord, time.Now().Format("20060102-150405"))
-	_ = saveImageData(name, buf.Bytes())
+	if err := encodeImage(&buf, img, g.ssFormat, ScreenshotJPEGQualities[g.ssJPEGQuality], meta); err != nil {
+		return fmt.Errorf("encode %s: %v", g.ssFormat, err)
+	}
+	name := fmt.Sprintf("%s-%s%s", g.coord, now.Format("20060102-150405"), g.ssFormat.ext())
+	return saveImageData(name, buf.Bytes())
 }
 
 func (g *Game) captureScreenshot(w, h int, zoom float64) *image.RGBA {
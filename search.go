@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// seedRange is an inclusive range of seed numbers.
+type seedRange struct {
+	From, To int
+}
+
+// parseSeedRanges parses a list such as "1000-5000,7000".
+func parseSeedRanges(s string) ([]seedRange, error) {
+	var out []seedRange
+	for _, part := range splitList(s) {
+		from, to, isRange := strings.Cut(part, "-")
+		lo, err := strconv.Atoi(strings.TrimSpace(from))
+		if err != nil || lo < 0 {
+			return nil, fmt.Errorf("invalid seed %q", part)
+		}
+		hi := lo
+		if isRange {
+			hi, err = strconv.Atoi(strings.TrimSpace(to))
+			if err != nil || hi < lo {
+				return nil, fmt.Errorf("invalid seed range %q", part)
+			}
+		}
+		if hi > maxSeed {
+			return nil, fmt.Errorf("seed %d is out of range", hi)
+		}
+		out = append(out, seedRange{lo, hi})
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("no seeds given")
+	}
+	return out, nil
+}
+
+// expandSeeds sends the coordinate of template with each seed number of
+// ranges to out, stopping early when ctx is cancelled.
+func expandSeeds(ctx context.Context, template Coordinate, ranges []seedRange, out chan<- string) {
+	for _, r := range ranges {
+		for seed := r.From; seed <= r.To; seed++ {
+			c := template
+			c.Seed = seed
+			select {
+			case out <- c.String():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// searchOptions configures searchSeeds.
+type searchOptions struct {
+	// Workers is the number of seeds loaded in parallel.
+	Workers int
+	// Rate limits network downloads to this many per second; zero or less
+	// disables the limit. Seeds already in the cache are not limited.
+	Rate float64
+	// Asteroids selects the asteroids checked in each seed, see
+	// selectAsteroids.
+	Asteroids []string
+	Rules     scoreRuleSet
+	// MinScore accepts asteroids scoring at least this much. When negative
+	// every rule must pass.
+	MinScore int
+}
+
+// searchResult is the outcome for one coordinate. Report is set for matches
+// and Err when the seed could not be loaded.
+type searchResult struct {
+	Coord  string
+	Report *scoreReport
+	Seed   *SeedData
+	Err    error
+}
+
+// matches reports whether rep satisfies the search criteria.
+func (o searchOptions) matches(rep scoreReport) bool {
+	if o.MinScore >= 0 {
+		return rep.Score >= o.MinScore
+	}
+	for _, res := range rep.Results {
+		if !res.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// searchSeeds loads every coordinate received from coords with a bounded
+// worker pool and calls emit for each match and each error. emit is never
+// called concurrently. It returns the number of seeds checked once coords
+// is closed and all workers have finished.
+func searchSeeds(ctx context.Context, coords <-chan string, opts searchOptions, emit func(searchResult)) int {
+	workers := max(1, opts.Workers)
+	var tick <-chan time.Time
+	if opts.Rate > 0 {
+		t := time.NewTicker(time.Duration(float64(time.Second) / opts.Rate))
+		defer t.Stop()
+		tick = t.C
+	}
+	var (
+		wg      sync.WaitGroup
+		emitMu  sync.Mutex
+		checked int
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for coord := range coords {
+				if ctx.Err() != nil {
+					continue
+				}
+				res := searchSeed(ctx, coord, opts, tick)
+				emitMu.Lock()
+				checked++
+				if res.Err != nil || res.Report != nil {
+					emit(res)
+				}
+				emitMu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return checked
+}
+
+// searchSeed loads one seed, waiting for tick before any download, and
+// checks its asteroids.
+func searchSeed(ctx context.Context, coord string, opts searchOptions, tick <-chan time.Time) searchResult {
+	res := searchResult{Coord: coord}
+	if tick != nil && !seedCached(coord) {
+		select {
+		case <-tick:
+		case <-ctx.Done():
+			res.Err = ctx.Err()
+			return res
+		}
+	}
+	seed, err := loadSeed(ctx, seedSource{coord: coord})
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	asts, err := selectAsteroids(seed, opts.Asteroids)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	for _, a := range asts {
+		rep := opts.Rules.evaluate(seed.Asteroids, a)
+		if opts.matches(rep) {
+			res.Report = &rep
+			res.Seed = seed
+			break
+		}
+	}
+	return res
+}
+
+// searchSummary describes a matching asteroid in one line, for example
+// "5/5 Terra: 12 geysers, 31 POIs, traits: Geoactive".
+func searchSummary(res searchResult) string {
+	rep := res.Report
+	idx := asteroidIndexByID(res.Seed.Asteroids, rep.Asteroid)
+	a := res.Seed.Asteroids[idx]
+	s := fmt.Sprintf("%d/%d %s: %d geysers, %d POIs", rep.Score, rep.MaxScore, a.ID, len(a.Geysers), len(a.POIs))
+	if len(a.Traits) > 0 {
+		s += ", traits: " + worldTraitNames(a.Traits)
+	}
+	return s
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+)
+
+// stringList is a flag that may be repeated.
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+
+func (l *stringList) Set(s string) error {
+	*l = append(*l, s)
+	return nil
+}
+
+// searchFilterFlags are the quick filters of the search command. Each one
+// becomes a rule that must pass.
+type searchFilterFlags struct {
+	geysers, noGeysers, pois, traits, noTraits stringList
+}
+
+func addSearchFilterFlags(fs *flag.FlagSet) *searchFilterFlags {
+	f := &searchFilterFlags{}
+	fs.Var(&f.geysers, "geyser", "require a geyser type, as ID or ID:COUNT for a minimum count (repeatable)")
+	fs.Var(&f.noGeysers, "no-geyser", "reject seeds with this geyser type (repeatable)")
+	fs.Var(&f.pois, "poi", "require a POI type (repeatable)")
+	fs.Var(&f.traits, "trait", "require a world trait such as MetalPoor (repeatable)")
+	fs.Var(&f.noTraits, "no-trait", "reject seeds with this world trait (repeatable)")
+	return f
+}
+
+// rules converts the filters to score rules.
+func (f *searchFilterFlags) rules() ([]scoreRule, error) {
+	var out []scoreRule
+	zero := 0
+	for _, g := range f.geysers {
+		id, count := g, 1
+		if i := strings.LastIndex(g, ":"); i >= 0 {
+			n, err := strconv.Atoi(g[i+1:])
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("invalid geyser count in %q", g)
+			}
+			id, count = g[:i], n
+		}
+		out = append(out, scoreRule{Name: fmt.Sprintf("at least %d %s", count, id), Geysers: []string{id}, Min: &count})
+	}
+	for _, id := range f.noGeysers {
+		out = append(out, scoreRule{Name: "no " + id, Geysers: []string{id}, Max: &zero})
+	}
+	for _, id := range f.pois {
+		out = append(out, scoreRule{Name: id, POIs: []string{id}})
+	}
+	for _, id := range f.traits {
+		out = append(out, scoreRule{Name: id, Traits: []string{id}})
+	}
+	for _, id := range f.noTraits {
+		out = append(out, scoreRule{Name: "no " + id, Traits: []string{id}, Max: &zero})
+	}
+	for i := range out {
+		if err := out[i].validate(); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// runSearch implements the "search" subcommand which checks many seeds and
+// prints those matching the filters as they are found.
+func runSearch(args []string) int {
+	fs := newCommandFlags("search", "search [flags] COORD... | -seeds RANGE TEMPLATE... | -list FILE")
+	src := addSeedSourceFlags(fs)
+	filters := addSearchFilterFlags(fs)
+	seeds := fs.String("seeds", "", "seed numbers to search for each TEMPLATE coordinate, e.g. 1000-5000,7000")
+	list := fs.String("list", "", "file with one coordinate per line, or - for stdin")
+	workers := fs.Int("workers", SearchWorkers, "number of seeds loaded in parallel")
+	rate := fs.Float64("rate", SearchRate, "maximum downloads per second (0 for no limit)")
+	asteroids := fs.String("asteroid", "", "comma separated asteroid IDs to check, or \"all\" to match any asteroid (default: the starting asteroid)")
+	rulesPath := fs.String("rules", "", "YAML or JSON rule file; every rule must pass unless -min-score is set")
+	minScore := fs.Int("min-score", -1, "accept seeds scoring at least this many points with -rules")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	src.apply()
+	if fs.NArg() == 0 && *list == "" {
+		fs.Usage()
+		return 2
+	}
+	opts := searchOptions{
+		Workers:   *workers,
+		Rate:      *rate,
+		Asteroids: splitList(*asteroids),
+		MinScore:  *minScore,
+	}
+	if *rulesPath != "" {
+		rs, err := loadScoreRules(*rulesPath)
+		if err != nil {
+			commandError(os.Stderr, "search", err)
+			return 2
+		}
+		opts.Rules = rs
+	}
+	extra, err := filters.rules()
+	if err != nil {
+		commandError(os.Stderr, "search", err)
+		return 2
+	}
+	opts.Rules.Rules = append(opts.Rules.Rules, extra...)
+
+	var ranges []seedRange
+	var templates []Coordinate
+	if *seeds != "" {
+		if ranges, err = parseSeedRanges(*seeds); err != nil {
+			commandError(os.Stderr, "search", err)
+			return 2
+		}
+		for _, arg := range fs.Args() {
+			c, err := ParseCoordinate(arg)
+			if err != nil {
+				commandError(os.Stderr, "search", fmt.Errorf("%s: %v", arg, err))
+				return 2
+			}
+			templates = append(templates, c)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	coords := make(chan string)
+	go func() {
+		defer close(coords)
+		if *list != "" {
+			if err := sendCoordList(ctx, *list, coords); err != nil {
+				commandError(os.Stderr, "search", err)
+			}
+		}
+		if ranges != nil {
+			for _, t := range templates {
+				expandSeeds(ctx, t, ranges, coords)
+			}
+			return
+		}
+		for _, arg := range fs.Args() {
+			select {
+			case coords <- strings.ToUpper(strings.TrimSpace(arg)):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	matched, failed := 0, 0
+	checked := searchSeeds(ctx, coords, opts, func(res searchResult) {
+		if res.Err != nil {
+			failed++
+			commandError(os.Stderr, "search", fmt.Errorf("%s: %v", res.Coord, res.Err))
+			return
+		}
+		matched++
+		fmt.Printf("%s\t%s\n", res.Coord, searchSummary(res))
+	})
+	fmt.Fprintf(os.Stderr, "search: checked %d seeds, %d matched, %d failed\n", checked, matched, failed)
+	if ctx.Err() != nil || (checked > 0 && failed == checked) {
+		return 1
+	}
+	return 0
+}
+
+// sendCoordList sends the coordinates listed in path to out. Blank lines and
+// lines starting with # are skipped.
+func sendCoordList(ctx context.Context, path string, out chan<- string) error {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		select {
+		case out <- strings.ToUpper(line):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return sc.Err()
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	seedpb "oni-view/data/pb"
+)
+
+func TestParseSeedRanges(t *testing.T) {
+	got, err := parseSeedRanges("1000-1002, 7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0] != (seedRange{1000, 1002}) || got[1] != (seedRange{7, 7}) {
+		t.Fatalf("unexpected ranges %v", got)
+	}
+	for _, bad := range []string{"", "x", "5-1", "1-99999999999"} {
+		if _, err := parseSeedRanges(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+// TestSearchSeeds runs a search against a fixture server where even seeds
+// have a Water Geyser and seed 4 is missing.
+func TestSearchSeeds(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		maxInFlight = max(maxInFlight, inFlight)
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+		time.Sleep(5 * time.Millisecond)
+
+		c, err := ParseCoordinate(strings.TrimPrefix(r.URL.Path, "/"))
+		if err != nil || c.Seed == 4 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		ast := &seedpb.Asteroid{SizeX: 10, SizeY: 10, Geysers: []*seedpb.Geyser{{Id: 0}}}
+		if c.Seed%2 == 0 {
+			ast.Geysers = append(ast.Geysers, &seedpb.Geyser{Id: 9})
+		}
+		data, _ := proto.Marshal(&seedpb.Cluster{Asteroids: []*seedpb.Asteroid{ast}})
+		w.Write(data)
+	}))
+	defer srv.Close()
+	useTestFetchPolicy(t, srv.URL, seedFetchPolicy{Timeout: time.Second, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+	oldCache := seedProtoCache
+	seedProtoCache = newSeedCache("")
+	defer func() { seedProtoCache = oldCache }()
+
+	filters := &searchFilterFlags{geysers: stringList{"hot_water"}}
+	rules, err := filters.rules()
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := searchOptions{Workers: 2, Rate: 1000, MinScore: -1, Rules: scoreRuleSet{Rules: rules}}
+	template, _ := ParseCoordinate("SNDST-A-1-0-0-0")
+	coords := make(chan string)
+	ctx := context.Background()
+	go func() {
+		defer close(coords)
+		expandSeeds(ctx, template, []seedRange{{1, 6}}, coords)
+	}()
+	var matched, failed []string
+	checked := searchSeeds(ctx, coords, opts, func(res searchResult) {
+		if res.Err != nil {
+			failed = append(failed, res.Coord)
+			return
+		}
+		matched = append(matched, res.Coord)
+		if s := searchSummary(res); !strings.HasPrefix(s, "1/1 Terra: 2 geysers") {
+			t.Errorf("unexpected summary %q", s)
+		}
+	})
+	sort.Strings(matched)
+	if checked != 6 {
+		t.Errorf("checked %d seeds, want 6", checked)
+	}
+	if strings.Join(matched, " ") != "SNDST-A-2-0-0-0 SNDST-A-6-0-0-0" {
+		t.Errorf("matched %v", matched)
+	}
+	if len(failed) != 1 || failed[0] != "SNDST-A-4-0-0-0" {
+		t.Errorf("failed %v", failed)
+	}
+	if maxInFlight > 2 {
+		t.Errorf("%d requests in flight, want at most 2", maxInFlight)
+	}
+}
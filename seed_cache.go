@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	seedpb "oni-view/data/pb"
+)
+
+// seedCache stores downloaded seed protobufs on disk so coordinates that were
+// viewed before can be opened without network access. Blobs are content
+// addressed by their SHA-256 hash and a small JSON index entry per coordinate
+// records which blob it maps to along with the fetch time and ETag.
+type seedCache struct {
+	dir string
+}
+
+// seedCacheEntry is the index record stored for each cached coordinate.
+type seedCacheEntry struct {
+	Coord   string    `json:"coord"`
+	Hash    string    `json:"sha256"`
+	ETag    string    `json:"etag,omitempty"`
+	Fetched time.Time `json:"fetched"`
+}
+
+var errSeedNotCached = errors.New("seed not in cache")
+
+var seedProtoCache = newSeedCache(defaultSeedCacheDir())
+
+// offlineMode serves seed data only from the local cache.
+var offlineMode bool
+
+// refreshSeedCache revalidates cached seeds with the server instead of
+// serving them directly.
+var refreshSeedCache bool
+
+// newSeedCache returns a cache rooted at dir. An empty dir disables caching.
+func newSeedCache(dir string) *seedCache {
+	return &seedCache{dir: dir}
+}
+
+func (c *seedCache) enabled() bool {
+	return c != nil && c.dir != ""
+}
+
+// cacheKey normalizes a coordinate and hashes it so arbitrary user input can
+// never escape the cache directory.
+func cacheKey(coord string) string {
+	sum := sha256.Sum256([]byte(strings.ToUpper(strings.TrimSpace(coord))))
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *seedCache) indexPath(coord string) string {
+	return filepath.Join(c.dir, "index", cacheKey(coord)+".json")
+}
+
+func (c *seedCache) blobPath(hash string) string {
+	return filepath.Join(c.dir, "blobs", hash+".pb")
+}
+
+// get returns the index entry and protobuf data cached for coord. Entries whose
+// blob is missing or does not match its recorded hash are treated as misses,
+// and entries whose blob does not decode are removed.
+func (c *seedCache) get(coord string) (seedCacheEntry, []byte, error) {
+	var entry seedCacheEntry
+	if !c.enabled() {
+		return entry, nil, errSeedNotCached
+	}
+	raw, err := os.ReadFile(c.indexPath(coord))
+	if err != nil {
+		return entry, nil, errSeedNotCached
+	}
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return seedCacheEntry{}, nil, errSeedNotCached
+	}
+	data, err := os.ReadFile(c.blobPath(entry.Hash))
+	if err != nil {
+		return entry, nil, errSeedNotCached
+	}
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != entry.Hash {
+		return entry, nil, errSeedNotCached
+	}
+	if checkSeedProto(data) != nil {
+		os.Remove(c.indexPath(coord))
+		return seedCacheEntry{}, nil, errSeedNotCached
+	}
+	return entry, data, nil
+}
+
+// checkSeedProto reports whether data decodes as a seed so error pages and
+// truncated downloads are never cached.
+func checkSeedProto(data []byte) error {
+	var pb seedpb.Cluster
+	if err := proto.Unmarshal(data, &pb); err != nil {
+		return fmt.Errorf("protobuf decode failed: %v", err)
+	}
+	return nil
+}
+
+// has reports whether coord has an index entry whose blob exists. Unlike get
+// it does not read or verify the blob, so a corrupt blob still counts.
+func (c *seedCache) has(coord string) bool {
+	if !c.enabled() {
+		return false
+	}
+	raw, err := os.ReadFile(c.indexPath(coord))
+	if err != nil {
+		return false
+	}
+	var entry seedCacheEntry
+	if json.Unmarshal(raw, &entry) != nil {
+		return false
+	}
+	_, err = os.Stat(c.blobPath(entry.Hash))
+	return err == nil
+}
+
+// put stores data for coord and updates its index entry.
+func (c *seedCache) put(coord string, data []byte, etag string) error {
+	if !c.enabled() {
+		return nil
+	}
+	sum := sha256.Sum256(data)
+	entry := seedCacheEntry{
+		Coord:   strings.TrimSpace(coord),
+		Hash:    hex.EncodeToString(sum[:]),
+		ETag:    etag,
+		Fetched: time.Now().UTC(),
+	}
+	if err := writeFileAtomic(c.blobPath(entry.Hash), data); err != nil {
+		return err
+	}
+	return c.putEntry(entry)
+}
+
+func (c *seedCache) putEntry(entry seedCacheEntry) error {
+	raw, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(c.indexPath(entry.Coord), raw)
+}
+
+// writeFileAtomic writes data to a temporary file and renames it into place so
+// readers never observe a partially written file.
+func writeFileAtomic(name string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
+
+// seedCached reports whether loadSeedProto can return coord without a
+// download. It only checks the index so the blob is read once, by the load.
+func seedCached(coord string) bool {
+	if refreshSeedCache && !offlineMode {
+		return false
+	}
+	return seedProtoCache.has(coord)
+}
+
+// loadSeedProto returns the protobuf data for coord. The local cache is
+// consulted first and a hit is returned without contacting the server. In
+// offline mode the network is never used. status receives download progress
+// messages and may be nil.
+func loadSeedProto(ctx context.Context, coord string, status func(string)) ([]byte, error) {
+	entry, cached, cacheErr := seedProtoCache.get(coord)
+	if cacheErr == nil && (offlineMode || !refreshSeedCache) {
+		return cached, nil
+	}
+	if offlineMode {
+		return nil, fmt.Errorf("offline mode: %s is not in the local cache", coord)
+	}
+	etag := ""
+	if cacheErr == nil {
+		etag = entry.ETag
+	}
+	body, newETag, err := fetchSeedProtoContext(ctx, coord, etag, status)
+	if errors.Is(err, errSeedNotModified) {
+		entry.Fetched = time.Now().UTC()
+		_ = seedProtoCache.putEntry(entry)
+		return cached, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSeedProto(body); err != nil {
+		return nil, fmt.Errorf("%s: invalid seed data from server: %v", coord, err)
+	}
+	_ = seedProtoCache.put(coord, body, newETag)
+	return body, nil
+}
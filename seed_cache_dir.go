@@ -0,0 +1,18 @@
+//go:build !js
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// defaultSeedCacheDir returns the per-user directory used for cached seeds, or
+// "" when no cache directory is available.
+func defaultSeedCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, SeedCacheDirName)
+}
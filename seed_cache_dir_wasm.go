@@ -0,0 +1,9 @@
+//go:build js && wasm
+
+package main
+
+// defaultSeedCacheDir disables the on-disk seed cache in the browser, which
+// already caches responses itself.
+func defaultSeedCacheDir() string {
+	return ""
+}
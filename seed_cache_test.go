@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	seedpb "oni-view/data/pb"
+)
+
+// testSeedProto returns an encoded seed with one asteroid of the given width.
+func testSeedProto(t *testing.T, width int32) []byte {
+	t.Helper()
+	data, err := proto.Marshal(&seedpb.Cluster{Asteroids: []*seedpb.Asteroid{{SizeX: width, SizeY: 64}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// useTestSeedCache points the global seed cache at a temporary directory and
+// restores the previous cache settings when the test ends.
+func useTestSeedCache(t *testing.T, srvURL string) {
+	t.Helper()
+	oldCache, oldURL := seedProtoCache, seedProtoBaseURL
+	oldOffline, oldRefresh := offlineMode, refreshSeedCache
+	seedProtoCache = newSeedCache(t.TempDir())
+	seedProtoBaseURL = srvURL + "/"
+	t.Cleanup(func() {
+		seedProtoCache, seedProtoBaseURL = oldCache, oldURL
+		offlineMode, refreshSeedCache = oldOffline, oldRefresh
+	})
+}
+
+// TestLoadSeedProtoCacheHit verifies that a cached coordinate is served
+// without contacting the server again.
+func TestLoadSeedProtoCacheHit(t *testing.T) {
+	seed := testSeedProto(t, 256)
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Header().Set("ETag", `"v1"`)
+		w.Write(seed)
+	}))
+	defer srv.Close()
+	useTestSeedCache(t, srv.URL)
+
+	for i := 0; i < 3; i++ {
+		body, err := loadSeedProto(context.Background(), "SNDST-A-7-0-0-0", nil)
+		if err != nil {
+			t.Fatalf("loadSeedProto error: %v", err)
+		}
+		if !bytes.Equal(body, seed) {
+			t.Fatalf("unexpected body: %x", body)
+		}
+	}
+	if hits != 1 {
+		t.Fatalf("expected 1 server request, got %d", hits)
+	}
+	entry, _, err := seedProtoCache.get("sndst-a-7-0-0-0")
+	if err != nil {
+		t.Fatalf("cache lookup failed: %v", err)
+	}
+	if entry.ETag != `"v1"` || entry.Fetched.IsZero() {
+		t.Fatalf("unexpected cache entry: %+v", entry)
+	}
+}
+
+// TestLoadSeedProtoOffline verifies offline mode never touches the network.
+func TestLoadSeedProtoOffline(t *testing.T) {
+	cached := testSeedProto(t, 128)
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Write(testSeedProto(t, 256))
+	}))
+	defer srv.Close()
+	useTestSeedCache(t, srv.URL)
+	offlineMode = true
+
+	if _, err := loadSeedProto(context.Background(), "SNDST-A-7-0-0-0", nil); err == nil {
+		t.Fatal("expected error for uncached coordinate")
+	}
+	if err := seedProtoCache.put("SNDST-A-7-0-0-0", cached, ""); err != nil {
+		t.Fatalf("put failed: %v", err)
+	}
+	body, err := loadSeedProto(context.Background(), "SNDST-A-7-0-0-0", nil)
+	if err != nil || !bytes.Equal(body, cached) {
+		t.Fatalf("unexpected result %x, %v", body, err)
+	}
+	if hits != 0 {
+		t.Fatalf("offline mode contacted the server %d times", hits)
+	}
+}
+
+// TestLoadSeedProtoRevalidate verifies refresh mode sends the cached ETag and
+// keeps the cached data on 304 Not Modified.
+func TestLoadSeedProtoRevalidate(t *testing.T) {
+	cached := testSeedProto(t, 128)
+	var gotETag string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotETag = r.Header.Get("If-None-Match")
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer srv.Close()
+	useTestSeedCache(t, srv.URL)
+	refreshSeedCache = true
+
+	if err := seedProtoCache.put("SNDST-A-7-0-0-0", cached, `"v1"`); err != nil {
+		t.Fatalf("put failed: %v", err)
+	}
+	body, err := loadSeedProto(context.Background(), "SNDST-A-7-0-0-0", nil)
+	if err != nil || !bytes.Equal(body, cached) {
+		t.Fatalf("unexpected result %x, %v", body, err)
+	}
+	if gotETag != `"v1"` {
+		t.Fatalf("unexpected If-None-Match header: %q", gotETag)
+	}
+}
+
+// TestLoadSeedProtoInvalidBody verifies a 200 response that is not a seed
+// is reported and not cached, and that a corrupt cached blob is dropped.
+func TestLoadSeedProtoInvalidBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html><body>Bad Gateway</body></html>"))
+	}))
+	defer srv.Close()
+	useTestSeedCache(t, srv.URL)
+
+	if _, err := loadSeedProto(context.Background(), "SNDST-A-7-0-0-0", nil); err == nil {
+		t.Fatal("an HTML page was accepted as seed data")
+	}
+	if _, _, err := seedProtoCache.get("SNDST-A-7-0-0-0"); err == nil {
+		t.Fatal("an HTML page was cached")
+	}
+
+	// A blob written before validation existed is removed on read.
+	if err := seedProtoCache.put("SNDST-A-7-0-0-0", []byte("<html>"), ""); err != nil {
+		t.Fatalf("put failed: %v", err)
+	}
+	if _, _, err := seedProtoCache.get("SNDST-A-7-0-0-0"); err == nil {
+		t.Fatal("corrupt cached seed served")
+	}
+	if _, err := os.Stat(seedProtoCache.indexPath("SNDST-A-7-0-0-0")); !os.IsNotExist(err) {
+		t.Errorf("corrupt cache entry kept: %v", err)
+	}
+}
+
+func TestSeedCacheHas(t *testing.T) {
+	useTestSeedCache(t, "http://127.0.0.1:0")
+	if seedCached("SNDST-A-7-0-0-0") {
+		t.Fatal("empty cache reported a hit")
+	}
+	if err := seedProtoCache.put("SNDST-A-7-0-0-0", testSeedProto(t, 128), ""); err != nil {
+		t.Fatalf("put failed: %v", err)
+	}
+	if !seedCached("sndst-a-7-0-0-0") {
+		t.Fatal("cached seed not found")
+	}
+	refreshSeedCache = true
+	if seedCached("SNDST-A-7-0-0-0") {
+		t.Fatal("refresh mode should download")
+	}
+}
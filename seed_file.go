@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// readSeedFile loads a Cluster protobuf from disk. Files ending in .gz or
+// starting with the gzip magic bytes are decompressed transparently.
+func readSeedFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read seed file: %v", err)
+	}
+	if strings.HasSuffix(strings.ToLower(path), ".gz") || bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("gzip init failed: %v", err)
+		}
+		defer gz.Close()
+		data, err = io.ReadAll(gz)
+		if err != nil {
+			return nil, fmt.Errorf("gzip read failed: %v", err)
+		}
+	}
+	return data, nil
+}
+
+// coordFromFileName derives a seed coordinate from a file name such as
+// "SNDST-A-7-0-0-0.pb.gz".
+func coordFromFileName(path string) string {
+	name := filepath.Base(path)
+	for _, ext := range []string{".gz", ".pb"} {
+		if strings.HasSuffix(strings.ToLower(name), ext) {
+			name = name[:len(name)-len(ext)]
+		}
+	}
+	return name
+}
+
+// loadSeedData returns protobuf data for a seed, reading file when it is set
+// and otherwise loading coord through the cache and network. coord is
+// validated first so malformed input never reaches the server.
+func loadSeedData(ctx context.Context, coord, file string, status func(string)) ([]byte, error) {
+	if file != "" {
+		return readSeedFile(file)
+	}
+	c, err := ParseCoordinate(coord)
+	if err != nil {
+		return nil, fmt.Errorf("invalid coordinate: %v", err)
+	}
+	return loadSeedProto(ctx, c.String(), status)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadSeedFile(t *testing.T) {
+	payload := []byte{0x0a, 0x0f, 'S', 'N', 'D', 'S', 'T', '-', 'A', '-', '7', '-', '0', '-', '0', '-', '0'}
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	w.Write(payload)
+	w.Close()
+
+	dir := t.TempDir()
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"SNDST-A-7-0-0-0.pb", payload},
+		{"SNDST-A-7-0-0-0.pb.gz", gz.Bytes()},
+		// Detected by the gzip magic bytes without the suffix.
+		{"compressed.pb", gz.Bytes()},
+		{"UPPER.PB.GZ", gz.Bytes()},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(dir, tt.name)
+		if err := os.WriteFile(path, tt.data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		got, err := readSeedFile(path)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !bytes.Equal(got, payload) {
+			t.Errorf("%s: got %x, want %x", tt.name, got, payload)
+		}
+	}
+
+	bad := filepath.Join(dir, "broken.pb.gz")
+	if err := os.WriteFile(bad, payload, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readSeedFile(bad); err == nil {
+		t.Error("plain data with a .gz suffix was accepted")
+	}
+	if _, err := readSeedFile(filepath.Join(dir, "missing.pb")); err == nil {
+		t.Error("missing file was accepted")
+	}
+}
+
+func TestCoordFromFileName(t *testing.T) {
+	tests := []struct {
+		path, want string
+	}{
+		{"SNDST-A-7-0-0-0.pb", "SNDST-A-7-0-0-0"},
+		{"seeds/V-FRST-C-1331877-0-0-0.pb.gz", "V-FRST-C-1331877-0-0-0"},
+		{"X.pb.gz", "X"},
+		{"X.PB.GZ", "X"},
+		{"X.gz", "X"},
+		{"X.json", "X.json"},
+	}
+	for _, tt := range tests {
+		if got := coordFromFileName(tt.path); got != tt.want {
+			t.Errorf("coordFromFileName(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
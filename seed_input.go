@@ -0,0 +1,278 @@
+package main
+
+import (
+	"image"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// seedKeyRows is the on-screen keyboard used for touch input. "<" deletes the
+// last character.
+var seedKeyRows = []string{
+	"1234567890",
+	"QWERTYUIOP",
+	"ASDFGHJKL-",
+	"ZXCVBNM<",
+}
+
+type seedKey struct {
+	rect  image.Rectangle
+	label string
+}
+
+// seedInputLayout holds the screen rectangles of the seed entry overlay so
+// drawing and hit testing stay in sync.
+type seedInputLayout struct {
+	frame   image.Rectangle
+	input   image.Rectangle
+	load    image.Rectangle
+	cancel  image.Rectangle
+	keys    []seedKey
+	recent  []image.Rectangle
+	titleY  int
+	errorY  int
+	recentY int
+}
+
+func (g *Game) showKeyboard() bool {
+	return g.mobile || g.touchUsed
+}
+
+func (g *Game) seedInputLayout() seedInputLayout {
+	var l seedInputLayout
+	pad := uiScaled(6)
+	w := uiScaled(SeedInputWidth)
+	for _, s := range g.recentSeeds {
+		if tw, _ := textDimensions(s); tw+pad*4 > w {
+			w = tw + pad*4
+		}
+	}
+	if w > g.width-pad*2 {
+		w = g.width - pad*2
+	}
+	x := g.width/2 - w/2
+	y := uiScaled(40)
+	l.titleY = y + pad
+	y = l.titleY + menuSpacing()
+	l.input = image.Rect(x+pad, y, x+w-pad, y+menuButtonHeight())
+	y = l.input.Max.Y + uiScaled(4)
+	l.errorY = y
+	y += menuSpacing()
+	half := (w - pad*3) / 2
+	l.load = image.Rect(x+pad, y, x+pad+half, y+menuButtonHeight())
+	l.cancel = image.Rect(l.load.Max.X+pad, y, x+w-pad, y+menuButtonHeight())
+	y += menuSpacing()
+	if g.showKeyboard() {
+		size := (w - pad*2) / len(seedKeyRows[0])
+		for _, row := range seedKeyRows {
+			for i, r := range row {
+				kx := x + pad + i*size
+				l.keys = append(l.keys, seedKey{rect: image.Rect(kx+1, y+1, kx+size-1, y+size-1), label: string(r)})
+			}
+			y += size
+		}
+		y += uiScaled(4)
+	}
+	if len(g.recentSeeds) > 0 {
+		l.recentY = y
+		y += menuSpacing()
+		for range g.recentSeeds {
+			l.recent = append(l.recent, image.Rect(x+pad, y, x+w-pad, y+menuButtonHeight()))
+			y += menuSpacing()
+		}
+	}
+	l.frame = image.Rect(x, uiScaled(40), x+w, y+pad)
+	return l
+}
+
+func (g *Game) openSeedInput() {
+	g.closeMenus()
+	g.showSeedInput = true
+	g.seedInput = g.coord
+	g.seedInputErr = ""
+	g.needsRedraw = true
+}
+
+func (g *Game) closeSeedInput() {
+	g.showSeedInput = false
+	g.compareInput = false
+	g.seedInputErr = ""
+	g.needsRedraw = true
+}
+
+// submitSeedInput validates the typed coordinate and starts loading it.
+func (g *Game) submitSeedInput() {
+	c, err := ParseCoordinate(g.seedInput)
+	if err != nil {
+		g.seedInputErr = err.Error()
+		g.needsRedraw = true
+		return
+	}
+	if g.compareInput {
+		g.closeSeedInput()
+		g.openCompare(c.String())
+		return
+	}
+	g.closeSeedInput()
+	g.asteroidSpecified = false
+	g.seedFile = ""
+	loadGameData(g, c.String(), "")
+}
+
+// addRecentSeed moves coord to the front of the recent seed history.
+func (g *Game) addRecentSeed(coord string) {
+	list := []string{coord}
+	for _, s := range g.recentSeeds {
+		if !strings.EqualFold(s, coord) {
+			list = append(list, s)
+		}
+	}
+	if len(list) > RecentSeedLimit {
+		list = list[:RecentSeedLimit]
+	}
+	g.recentSeeds = list
+}
+
+func (g *Game) typeSeedChar(r rune) {
+	if r >= 'a' && r <= 'z' {
+		r -= 'a' - 'A'
+	}
+	if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' {
+		if len(g.seedInput) < SeedInputMaxLen {
+			g.seedInput += string(r)
+			g.seedInputErr = ""
+			g.needsRedraw = true
+		}
+	}
+}
+
+func (g *Game) deleteSeedChar() {
+	if g.seedInput != "" {
+		g.seedInput = g.seedInput[:len(g.seedInput)-1]
+		g.seedInputErr = ""
+		g.needsRedraw = true
+	}
+}
+
+// keyRepeat reports whether a held key should trigger this tick.
+func keyRepeat(key ebiten.Key) bool {
+	d := inpututil.KeyPressDuration(key)
+	return d == 1 || (d > 30 && d%3 == 0)
+}
+
+// handleSeedInput processes input for the seed entry overlay. It opens the
+// overlay with the L key or a click on the error screen and returns true while
+// the overlay is consuming input.
+func (g *Game) handleSeedInput() bool {
+	if !g.showSeedInput {
+		errorScreen := !g.loading && len(g.biomes) == 0 && g.status != ""
+		if (inpututil.IsKeyJustPressed(ebiten.KeyL) && !g.textInputActive()) || (errorScreen && (inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) || len(inpututil.AppendJustPressedTouchIDs(nil)) > 0)) {
+			g.openSeedInput()
+			return true
+		}
+		return false
+	}
+	for _, r := range ebiten.AppendInputChars(nil) {
+		g.typeSeedChar(r)
+	}
+	if keyRepeat(ebiten.KeyBackspace) {
+		g.deleteSeedChar()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyNumpadEnter) {
+		g.submitSeedInput()
+		return true
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		g.closeSeedInput()
+		return true
+	}
+	var clicks []image.Point
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		mx, my := ebiten.CursorPosition()
+		clicks = append(clicks, image.Pt(mx, my))
+	}
+	for _, id := range inpututil.AppendJustPressedTouchIDs(nil) {
+		x, y := ebiten.TouchPosition(id)
+		clicks = append(clicks, image.Pt(x, y))
+		g.touchUsed = true
+	}
+	for _, pt := range clicks {
+		g.clickSeedInput(pt)
+	}
+	return true
+}
+
+func (g *Game) clickSeedInput(pt image.Point) {
+	l := g.seedInputLayout()
+	switch {
+	case pt.In(l.load):
+		g.submitSeedInput()
+		return
+	case pt.In(l.cancel) || !pt.In(l.frame):
+		g.closeSeedInput()
+		return
+	}
+	for _, k := range l.keys {
+		if pt.In(k.rect) {
+			if k.label == "<" {
+				g.deleteSeedChar()
+			} else {
+				g.typeSeedChar(rune(k.label[0]))
+			}
+			return
+		}
+	}
+	for i, r := range l.recent {
+		if pt.In(r) && i < len(g.recentSeeds) {
+			g.seedInput = g.recentSeeds[i]
+			g.submitSeedInput()
+			return
+		}
+	}
+}
+
+func (g *Game) drawSeedInput(dst *ebiten.Image) {
+	l := g.seedInputLayout()
+	pad := uiScaled(6)
+	drawFrame(dst, l.frame)
+	title := SeedInputTitle
+	if g.compareInput {
+		title = CompareInputTitle
+	}
+	drawText(dst, title, l.frame.Min.X+pad, l.titleY, false)
+
+	drawButton(dst, l.input, false)
+	lh := menuButtonHeight() - 5
+	if notoFont != nil {
+		lh = notoFont.Metrics().Height.Ceil()
+	}
+	textY := func(r image.Rectangle) int { return r.Min.Y + (r.Dy()-lh)/2 }
+	drawText(dst, g.seedInput+"_", l.input.Min.X+pad, textY(l.input), false)
+	if g.seedInputErr != "" {
+		drawTextWithBGBorder(dst, g.seedInputErr, l.frame.Min.X+pad, l.errorY, errorBorderColor, false)
+	}
+
+	drawButton(dst, l.load, true)
+	drawText(dst, "Load", l.load.Min.X+l.load.Dx()/2, textY(l.load), true)
+	drawButton(dst, l.cancel, false)
+	drawText(dst, ScreenshotCancelLabel, l.cancel.Min.X+l.cancel.Dx()/2, textY(l.cancel), true)
+
+	for _, k := range l.keys {
+		drawButton(dst, k.rect, false)
+		label := k.label
+		if label == "<" {
+			label = "Del"
+		}
+		drawText(dst, label, k.rect.Min.X+k.rect.Dx()/2, textY(k.rect), true)
+	}
+
+	if len(l.recent) > 0 {
+		drawText(dst, "Recent seeds:", l.frame.Min.X+pad, l.recentY, false)
+		for i, r := range l.recent {
+			drawButton(dst, r, strings.EqualFold(g.recentSeeds[i], g.coord))
+			drawText(dst, g.recentSeeds[i], r.Min.X+pad, textY(r), false)
+		}
+	}
+}
@@ -0,0 +1,353 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// loadPhase identifies the step a background seed load is currently in.
+type loadPhase int
+
+const (
+	phaseFetching loadPhase = iota
+	phaseDecoding
+	phaseIcons
+)
+
+// seedLoad tracks a seed being loaded on a background goroutine. Progress
+// fields are guarded by mu; the result is delivered once on done and applied
+// to the Game from Update so game state is only touched on the game goroutine.
+type seedLoad struct {
+	coord             string
+	asteroidID        string
+	asteroidSpecified bool
+	file              string
+	cancel            context.CancelFunc
+	done              chan seedLoadResult
+
+	mu     sync.Mutex
+	phase  loadPhase
+	status string
+	read   int64
+	total  int64
+}
+
+type seedLoadResult struct {
+	seed     *SeedData
+	icons    map[string]*ebiten.Image
+	textures map[string]*ebiten.Image
+	err      error
+}
+
+func (l *seedLoad) setStatus(msg string) {
+	l.mu.Lock()
+	l.status = msg
+	l.mu.Unlock()
+}
+
+func (l *seedLoad) setPhase(p loadPhase) {
+	l.mu.Lock()
+	l.phase = p
+	l.mu.Unlock()
+}
+
+func (l *seedLoad) setBytes(read, total int64) {
+	l.mu.Lock()
+	l.read, l.total = read, total
+	l.mu.Unlock()
+}
+
+// progress returns a status message and a completion fraction in [0,1], or -1
+// when the fraction is unknown.
+func (l *seedLoad) progress() (string, float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	switch l.phase {
+	case phaseDecoding:
+		return "Decoding seed data...", -1
+	case phaseIcons:
+		return "Loading icons...", -1
+	}
+	msg := l.status
+	if msg == "" {
+		msg = "Fetching..."
+	}
+	frac := -1.0
+	if l.read > 0 {
+		if l.total > 0 {
+			msg += fmt.Sprintf("\n%s of %s", formatBytes(l.read), formatBytes(l.total))
+			frac = float64(l.read) / float64(l.total)
+		} else {
+			msg += "\n" + formatBytes(l.read)
+		}
+	}
+	return msg, frac
+}
+
+// formatBytes renders a byte count using KB or MB.
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
+
+// run fetches, decodes and prepares assets for the seed. It executes on its
+// own goroutine.
+func (l *seedLoad) run(ctx context.Context) {
+	ctx = withDownloadProgress(ctx, l.setBytes)
+	data, err := loadSeedData(ctx, l.coord, l.file, l.setStatus)
+	if err != nil {
+		l.done <- seedLoadResult{err: err}
+		return
+	}
+	l.setPhase(phaseDecoding)
+	seed, err := decodeSeedProto(data)
+	if err != nil {
+		l.done <- seedLoadResult{err: err}
+		return
+	}
+	if ctx.Err() != nil {
+		l.done <- seedLoadResult{err: ctx.Err()}
+		return
+	}
+	l.setPhase(phaseIcons)
+	res := seedLoadResult{seed: seed, icons: make(map[string]*ebiten.Image)}
+	if idx := l.asteroidIndex(seed); idx >= 0 {
+		for _, name := range iconNamesForAsteroid(seed.Asteroids[idx]) {
+			img, _ := loadImageFile(name)
+			res.icons[name] = img
+		}
+		res.textures = loadBiomeTextures()
+	}
+	l.done <- res
+}
+
+// asteroidIndex returns the asteroid that should be shown for seed, or -1 if
+// a requested asteroid does not exist.
+func (l *seedLoad) asteroidIndex(seed *SeedData) int {
+	if len(seed.Asteroids) == 0 {
+		return -1
+	}
+	if l.asteroidSpecified {
+		return asteroidIndexByID(seed.Asteroids, l.asteroidID)
+	}
+	return 0
+}
+
+// loadGameData starts loading coord on a background goroutine, cancelling any
+// load already in progress. The current map stays in memory until the new
+// seed arrives so a cancelled load can fall back to it.
+func loadGameData(game *Game, coord, asteroidID string) {
+	game.cancelLoad()
+	game.load = startSeedLoad(coord, asteroidID, game.asteroidSpecified, game.seedFile)
+	game.loading = true
+	game.status = "Fetching..."
+	game.statusError = false
+	game.needsRedraw = true
+}
+
+// startSeedLoad begins loading a seed on a new goroutine. The result arrives
+// on the returned load's done channel.
+func startSeedLoad(coord, asteroidID string, asteroidSpecified bool, file string) *seedLoad {
+	ctx, cancel := context.WithCancel(context.Background())
+	l := &seedLoad{
+		coord:             coord,
+		asteroidID:        asteroidID,
+		asteroidSpecified: asteroidSpecified,
+		file:              file,
+		cancel:            cancel,
+		done:              make(chan seedLoadResult, 1),
+	}
+	go l.run(ctx)
+	return l
+}
+
+// cancelLoad aborts the in-flight load, if any.
+func (g *Game) cancelLoad() {
+	if g.load == nil {
+		return
+	}
+	g.load.cancel()
+	g.load = nil
+	g.loading = false
+	g.needsRedraw = true
+}
+
+// pollSeedLoad updates the loading status and applies a finished load. It
+// also handles Escape to cancel. It is called every Update.
+func (g *Game) pollSeedLoad() {
+	l := g.load
+	if l == nil {
+		if g.statusError && len(g.biomes) != 0 && time.Since(g.statusTime) >= LoadErrorDuration {
+			g.needsRedraw = true
+		}
+		return
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) && !g.showSeedInput {
+		g.cancelLoad()
+		if len(g.biomes) == 0 {
+			g.status = "Loading cancelled: " + l.coord
+			g.statusError = true
+		} else {
+			g.status = ""
+		}
+		return
+	}
+	select {
+	case res := <-l.done:
+		g.load = nil
+		l.cancel()
+		g.applySeedLoad(l, res)
+	default:
+		msg, frac := l.progress()
+		if msg != g.status || frac != g.loadFraction {
+			g.status = msg
+			g.loadFraction = frac
+			g.needsRedraw = true
+		}
+	}
+}
+
+// applySeedLoad installs a loaded seed on the game or reports its error.
+func (g *Game) applySeedLoad(l *seedLoad, res seedLoadResult) {
+	g.loading = false
+	g.loadFraction = -1
+	g.needsRedraw = true
+	if res.err != nil {
+		if errors.Is(res.err, context.Canceled) {
+			return
+		}
+		g.setLoadError("Error: " + res.err.Error())
+		return
+	}
+	seed := res.seed
+	astIdxSel := l.asteroidIndex(seed)
+	if astIdxSel < 0 {
+		if len(seed.Asteroids) == 0 {
+			g.setLoadError(fmt.Sprintf("%s\nThis location does not contain any asteroids", l.coord))
+			return
+		}
+		msg := fmt.Sprintf("%s\nAsteroid ID: %s\nThis location does not contain Asteroid ID: %s", l.coord, l.asteroidID, l.asteroidID)
+		valid := make([]string, 0, len(seed.Asteroids))
+		for _, a := range seed.Asteroids {
+			valid = append(valid, a.ID)
+		}
+		lines := make([]string, 0, (len(valid)+2)/3)
+		for i, v := range valid {
+			if i%3 == 0 {
+				lines = append(lines, v)
+			} else {
+				lines[len(lines)-1] = lines[len(lines)-1] + ", " + v
+			}
+		}
+		g.setLoadError(msg + fmt.Sprintf("\nValid IDs: %s", strings.Join(lines, "\n")))
+		return
+	}
+	g.coord = l.coord
+	g.seedFile = l.file
+	g.asteroids = seed.Asteroids
+	g.clusterThumbs = nil
+	g.status = ""
+	g.statusError = false
+	g.addRecentSeed(l.coord)
+	ebiten.SetWindowTitle(windowTitle(l.coord))
+	for name, img := range res.icons {
+		g.icons[name] = img
+	}
+	g.biomeTextures = res.textures
+	g.setAsteroid(seed.Asteroids[astIdxSel])
+	if c := g.pendingCompare; c != "" {
+		g.pendingCompare = ""
+		g.centerAndFit()
+		g.openCompare(c)
+	}
+}
+
+// setLoadError reports a failed load. The map shown before the load, if
+// any, stays in place with the error over it for LoadErrorDuration.
+func (g *Game) setLoadError(msg string) {
+	g.status = msg
+	g.statusError = true
+	g.statusTime = time.Now()
+	g.needsRedraw = true
+}
+
+// drawLoadError shows the error of a failed load over the map that was kept.
+// The loading screen shows it when there is no map.
+func (g *Game) drawLoadError(dst *ebiten.Image) {
+	if g.loading || !g.statusError || g.status == "" {
+		return
+	}
+	if time.Since(g.statusTime) >= LoadErrorDuration {
+		g.status = ""
+		g.statusError = false
+		return
+	}
+	msg := g.status + "\n\nPress L to enter another seed"
+	drawTextWithBGBorderScale(dst, msg, g.width/2, uiScaled(60), errorBorderColor, 1, true)
+}
+
+// setAsteroid makes ast the displayed asteroid and resets per-asteroid state.
+// Icons and textures must already be loaded.
+func (g *Game) setAsteroid(ast Asteroid) {
+	g.invalidateLegends()
+	// Reset cached legends so item numbering matches the newly loaded
+	// asteroid contents. These are rebuilt lazily when needed.
+	g.legendMap = nil
+	g.legendEntries = nil
+	g.legendColors = nil
+	g.selectedItem = -1
+	g.itemScroll = 0
+	g.asteroidID = ast.ID
+	bps := ast.BiomePaths.Paths
+	g.geysers = ast.Geysers
+	g.pois = ast.POIs
+	g.geyserItems = nil
+	g.geyserScroll = 0
+	g.flight = nil
+	if g.showFind && !g.findAll {
+		g.updateFindMatches()
+	}
+	g.biomes = bps
+	g.astWidth = ast.SizeX
+	g.astHeight = ast.SizeY
+	g.legend, g.legendBiomes = buildLegendImage(bps)
+	g.fitOnLoad = true
+	g.needsRedraw = true
+}
+
+// iconNamesForAsteroid lists the toolbar icons plus every geyser and POI icon
+// needed to draw ast.
+func iconNamesForAsteroid(ast Asteroid) []string {
+	names := []string{"../icons/camera.png", "../icons/help.png", "../icons/gear.png", "geyser_water.png"}
+	set := make(map[string]struct{})
+	for _, gy := range ast.Geysers {
+		if n := iconForGeyser(gy.ID); n != "" {
+			if _, ok := set[n]; !ok {
+				set[n] = struct{}{}
+				names = append(names, n)
+			}
+		}
+	}
+	for _, poi := range ast.POIs {
+		if n := iconForPOI(poi.ID); n != "" {
+			if _, ok := set[n]; !ok {
+				set[n] = struct{}{}
+				names = append(names, n)
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func loadedGame() *Game {
+	return &Game{
+		coord:      "SNDST-A-1-0-0-0",
+		seedFile:   "old.pb",
+		asteroidID: "SandstoneDefault",
+		asteroids:  []Asteroid{{ID: "SandstoneDefault"}},
+		geysers:    []Geyser{{ID: "steam", X: 1, Y: 2}},
+		biomes:     []BiomePath{{Name: "Sandstone"}},
+		loading:    true,
+	}
+}
+
+func checkMapKept(t *testing.T, g *Game) {
+	t.Helper()
+	if g.coord != "SNDST-A-1-0-0-0" || g.seedFile != "old.pb" || g.asteroidID != "SandstoneDefault" {
+		t.Errorf("current seed replaced: coord %q file %q asteroid %q", g.coord, g.seedFile, g.asteroidID)
+	}
+	if len(g.asteroids) != 1 || len(g.geysers) != 1 || len(g.biomes) != 1 {
+		t.Errorf("current map replaced: %d asteroids, %d geysers, %d biomes", len(g.asteroids), len(g.geysers), len(g.biomes))
+	}
+	if g.loading {
+		t.Error("still loading")
+	}
+}
+
+func TestApplySeedLoadError(t *testing.T) {
+	g := loadedGame()
+	l := &seedLoad{coord: "V-SNDST-C-2-0-0-0", file: "new.pb"}
+	g.applySeedLoad(l, seedLoadResult{err: errors.New("not found")})
+	checkMapKept(t, g)
+	if !g.statusError || !strings.Contains(g.status, "not found") {
+		t.Errorf("status %q error %v", g.status, g.statusError)
+	}
+}
+
+func TestApplySeedLoadCanceled(t *testing.T) {
+	g := loadedGame()
+	l := &seedLoad{coord: "V-SNDST-C-2-0-0-0"}
+	g.applySeedLoad(l, seedLoadResult{err: context.Canceled})
+	checkMapKept(t, g)
+	if g.statusError {
+		t.Errorf("cancel reported as error: %q", g.status)
+	}
+}
+
+func TestApplySeedLoadAsteroidNotFound(t *testing.T) {
+	seed := &SeedData{Asteroids: []Asteroid{{ID: "VanillaSandstoneCluster"}, {ID: "TundraMoonlet"}}}
+	tests := []struct {
+		name string
+		seed *SeedData
+		want string
+	}{
+		{"missing id", seed, "Valid IDs: VanillaSandstoneCluster, TundraMoonlet"},
+		{"no asteroids", &SeedData{}, "does not contain any asteroids"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := loadedGame()
+			l := &seedLoad{coord: "V-SNDST-C-2-0-0-0", asteroidID: "OilyMoonlet", asteroidSpecified: true, file: "new.pb"}
+			g.applySeedLoad(l, seedLoadResult{seed: tt.seed})
+			checkMapKept(t, g)
+			if !g.statusError || !strings.Contains(g.status, tt.want) {
+				t.Errorf("status %q error %v, want %q", g.status, g.statusError, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/json"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// settings are the options menu values kept between sessions. Fields
+// missing from the saved file keep their defaults.
+type settings struct {
+	Textures        bool    `json:"textures"`
+	Vsync           bool    `json:"vsync"`
+	ShowItemNames   bool    `json:"showItemNames"`
+	ShowLegend      bool    `json:"showLegend"`
+	UseNumbers      bool    `json:"useNumbers"`
+	ShowPodLines    bool    `json:"showPodLines"`
+	ShowAnnotations bool    `json:"showAnnotations"`
+	ShowGrid        bool    `json:"showGrid"`
+	FlipY           bool    `json:"flipY"`
+	IconScale       float64 `json:"iconScale"`
+	UIScale         float64 `json:"uiScale"`
+	SmartRender     bool    `json:"powerSaver"`
+	LinearFilter    bool    `json:"linearFiltering"`
+	HiDPI           bool    `json:"hidpi"`
+}
+
+// defaultSettings returns the settings of a fresh install. Item numbers are
+// off on mobile devices where the number legend takes too much room.
+func defaultSettings(mobile bool) settings {
+	return settings{
+		Textures:        true,
+		Vsync:           true,
+		ShowItemNames:   true,
+		ShowLegend:      true,
+		UseNumbers:      !mobile,
+		ShowPodLines:    true,
+		ShowAnnotations: true,
+		IconScale:       1.0,
+		UIScale:         1.0,
+		SmartRender:     true,
+		LinearFilter:    true,
+		HiDPI:           true,
+	}
+}
+
+// loadSettings returns the saved settings, or the defaults when none were
+// saved.
+func loadSettings(mobile bool) settings {
+	data, _ := readUserData(SettingsFile)
+	return parseSettings(data, mobile)
+}
+
+// parseSettings decodes saved settings on top of the defaults. Invalid data
+// yields the defaults.
+func parseSettings(data []byte, mobile bool) settings {
+	s := defaultSettings(mobile)
+	saved := s
+	if len(data) > 0 && json.Unmarshal(data, &saved) == nil {
+		s = saved
+	}
+	s.IconScale = max(s.IconScale, 0.25)
+	s.UIScale = max(s.UIScale, 0.5)
+	return s
+}
+
+func saveSettings(s settings) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeUserData(SettingsFile, data)
+}
+
+// currentSettings returns the settings in effect.
+func (g *Game) currentSettings() settings {
+	return settings{
+		Textures:        g.textures,
+		Vsync:           g.vsync,
+		ShowItemNames:   g.showItemNames,
+		ShowLegend:      g.showLegend,
+		UseNumbers:      g.useNumbers,
+		ShowPodLines:    g.showPodLines,
+		ShowAnnotations: g.showAnnotations,
+		ShowGrid:        g.showGrid,
+		FlipY:           g.flipY,
+		IconScale:       g.iconScale,
+		UIScale:         uiScale,
+		SmartRender:     g.smartRender,
+		LinearFilter:    g.linearFilter,
+		HiDPI:           g.hidpi,
+	}
+}
+
+// applySettings switches to s and remembers it as saved so it is not
+// written back immediately.
+func (g *Game) applySettings(s settings) {
+	g.textures = s.Textures
+	g.vsync = s.Vsync
+	g.showItemNames = s.ShowItemNames
+	g.showLegend = s.ShowLegend
+	g.useNumbers = s.UseNumbers
+	g.showPodLines = s.ShowPodLines
+	g.showAnnotations = s.ShowAnnotations
+	g.showGrid = s.ShowGrid
+	g.flipY = s.FlipY
+	g.iconScale = s.IconScale
+	g.smartRender = s.SmartRender
+	g.linearFilter = s.LinearFilter
+	g.hidpi = s.HiDPI
+	setUIScale(s.UIScale)
+	setHiDPI(g.hidpi)
+	ebiten.SetVsyncEnabled(g.vsync)
+	g.savedSettings = g.currentSettings()
+	g.needsRedraw = true
+}
+
+// resetSettings restores the defaults and saves them.
+func (g *Game) resetSettings() {
+	g.applySettings(defaultSettings(g.mobile))
+	_ = saveSettings(g.savedSettings)
+}
+
+// persistSettings saves the settings when an option changed since they were
+// last saved. Layers still switched by the startup deep link keep their
+// saved values.
+func (g *Game) persistSettings() {
+	s := g.currentSettings()
+	g.withoutLinkLayers(&s)
+	if s != g.savedSettings {
+		g.savedSettings = s
+		_ = saveSettings(s)
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseSettings(t *testing.T) {
+	if got := parseSettings(nil, false); got != defaultSettings(false) {
+		t.Errorf("no data = %+v, want the defaults", got)
+	}
+	if got := parseSettings([]byte("not json"), true); got != defaultSettings(true) {
+		t.Errorf("invalid data = %+v, want the defaults", got)
+	}
+
+	got := parseSettings([]byte(`{"textures": false, "showGrid": true, "uiScale": 1.5, "iconScale": 0}`), false)
+	want := defaultSettings(false)
+	want.Textures = false
+	want.ShowGrid = true
+	want.UIScale = 1.5
+	want.IconScale = 0.25
+	if got != want {
+		t.Errorf("partial data = %+v, want %+v", got, want)
+	}
+
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back := parseSettings(data, true); back != want {
+		t.Errorf("round trip = %+v, want %+v", back, want)
+	}
+}
@@ -0,0 +1,442 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/image/font"
+)
+
+// svgOptions controls the SVG exporter. The drawing is in world cells, so
+// the embedded renderOptions.Scale only sets the nominal size in pixels.
+type svgOptions struct {
+	renderOptions
+	// Gray converts the map to black and white like the screenshot option.
+	Gray bool
+	// Annotations are drawn over the map.
+	Annotations []annotation
+}
+
+// svgWriter collects the elements of an SVG document. Textures and icons
+// are embedded once in defs and referenced from the drawing, icons as
+// symbols so they can be used at any size.
+type svgWriter struct {
+	opts    svgOptions
+	body    strings.Builder
+	defs    strings.Builder
+	defined map[string]bool
+	face    font.Face
+}
+
+// writeAsteroidSVG writes ast as an SVG document with biome polygons,
+// geyser and POI icons, their names and a legend beside the map.
+func writeAsteroidSVG(w io.Writer, ast Asteroid, opts svgOptions) error {
+	if opts.Scale <= 0 {
+		opts.Scale = RenderScale
+	}
+	s := &svgWriter{opts: opts, defined: map[string]bool{}, face: renderFace(baseFontSize)}
+	defer s.face.Close()
+
+	width, height := float64(ast.SizeX), float64(ast.SizeY)
+	filter := ""
+	if opts.Gray {
+		s.defs.WriteString(`<filter id="gray" color-interpolation-filters="sRGB"><feColorMatrix type="matrix" values="` +
+			"0.299 0.587 0.114 0 0 0.299 0.587 0.114 0 0 0.299 0.587 0.114 0 0 0 0 0 1 0" + `"/></filter>` + "\n")
+		filter = ` filter="url(#gray)"`
+	}
+	fmt.Fprintf(&s.body, "<g%s>\n", filter)
+	fmt.Fprintf(&s.body, `<rect width="%s" height="%s" fill="%s"/>`+"\n", svgNum(width), svgNum(height), svgColor(backgroundColor))
+	fmt.Fprintf(&s.body, `<rect width="%s" height="%s" fill="%s"/>`+"\n", svgNum(width), svgNum(height), s.biomeFill("Space"))
+	s.body.WriteString(`<g id="biomes" stroke="#ffffff" stroke-linejoin="round" fill-rule="evenodd">` + "\n")
+	for _, bp := range ast.BiomePaths.Paths {
+		if d := svgPath(bp.Polygons); d != "" {
+			fmt.Fprintf(&s.body, `<path d="%s" fill="%s" stroke-width="1" vector-effect="non-scaling-stroke"><title>%s</title></path>`+"\n",
+				d, s.biomeFill(bp.Name), html.EscapeString(displayBiome(bp.Name)))
+		}
+	}
+	s.body.WriteString("</g>\n")
+
+	type item struct {
+		x, y int
+		icon string
+		name string
+	}
+	var items []item
+	for _, gy := range ast.Geysers {
+		items = append(items, item{gy.X, gy.Y, iconForGeyser(gy.ID), displayGeyser(gy.ID)})
+	}
+	for _, p := range ast.POIs {
+		items = append(items, item{p.X, p.Y, iconForPOI(p.ID), displayPOI(p.ID)})
+	}
+	iconSize := IconScale * BaseIconPixels / 2
+	if opts.Icons {
+		s.body.WriteString(`<g id="icons">` + "\n")
+		for _, it := range items {
+			s.icon(it.icon, float64(it.x), float64(it.y), iconSize, it.name)
+		}
+		s.body.WriteString("</g>\n")
+	}
+	if opts.Labels {
+		s.body.WriteString(`<g id="labels">` + "\n")
+		for _, it := range items {
+			text, _ := formatLabel(it.name)
+			s.label(text, float64(it.x), float64(it.y)+iconSize/2, true, nil)
+		}
+		s.body.WriteString("</g>\n")
+	}
+	if len(opts.Annotations) > 0 {
+		s.body.WriteString(`<g id="annotations">` + "\n")
+		for _, a := range opts.Annotations {
+			s.annotation(a)
+		}
+		s.body.WriteString("</g>\n")
+	}
+	if opts.Labels && opts.Title != "" {
+		s.label(opts.Title, 2, 2, false, nil)
+	}
+	if opts.Legend {
+		lw, lh := s.legend(ast, width+SVGLegendGap)
+		width += SVGLegendGap + lw
+		height = math.Max(height, lh)
+	}
+	s.body.WriteString("</g>\n")
+
+	var out bytes.Buffer
+	out.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	fmt.Fprintf(&out, `<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" width="%s" height="%s" viewBox="0 0 %s %s" font-family="Noto Sans, sans-serif">`+"\n",
+		svgNum(width*opts.Scale), svgNum(height*opts.Scale), svgNum(width), svgNum(height))
+	if opts.Title != "" {
+		fmt.Fprintf(&out, "<title>%s</title>\n", html.EscapeString(opts.Title))
+	}
+	if s.defs.Len() > 0 {
+		out.WriteString("<defs>\n" + s.defs.String() + "</defs>\n")
+	}
+	out.WriteString(s.body.String())
+	out.WriteString("</svg>\n")
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+// writeSVGFile writes ast as an SVG document to path, creating parent
+// directories.
+func writeSVGFile(path string, ast Asteroid, opts svgOptions) error {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := writeAsteroidSVG(f, ast, opts); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// saveSVG saves the shown asteroid as an SVG document from the screenshot
+// menu. The quality level sets its nominal size and the other screenshot
+// and display options carry over.
+func (g *Game) saveSVG() error {
+	ast := Asteroid{
+		ID:         g.asteroidID,
+		SizeX:      g.astWidth,
+		SizeY:      g.astHeight,
+		Geysers:    g.geysers,
+		POIs:       g.pois,
+		BiomePaths: BiomePathsCompact{Paths: g.biomes},
+	}
+	opts := svgOptions{
+		renderOptions: renderOptions{
+			Scale:    2 * ScreenshotScales[g.ssQuality],
+			Textures: g.textures,
+			Icons:    true,
+			Labels:   g.showItemNames,
+			Legend:   g.showLegend,
+			Title:    g.coord + " - " + g.asteroidID,
+		},
+		Gray: g.ssNoColor,
+	}
+	if g.ssAnnotations && g.showAnnotations {
+		opts.Annotations = g.currentAnnotations()
+	}
+	var buf bytes.Buffer
+	if err := writeAsteroidSVG(&buf, ast, opts); err != nil {
+		return fmt.Errorf("encode SVG: %v", err)
+	}
+	name := fmt.Sprintf("%s-%s%s", g.coord, time.Now().Format("20060102-150405"), formatSVG.ext())
+	return saveImageData(name, buf.Bytes())
+}
+
+// biomeFill returns the fill of a biome: its color, or with textures a
+// pattern of the biome texture tinted by that color.
+func (s *svgWriter) biomeFill(name string) string {
+	clr, ok := biomeColors[name]
+	if !ok {
+		clr = color.RGBA{60, 60, 60, 255}
+	}
+	if !s.opts.Textures {
+		return svgColor(clr)
+	}
+	id := "tex-" + sanitizeFileName(name)
+	if !s.defined[id] {
+		tex := renderAsset("../biomes/" + name + ".png")
+		if tex == nil {
+			return svgColor(clr)
+		}
+		href, err := svgImageData(tex)
+		if err != nil {
+			return svgColor(clr)
+		}
+		size := svgNum(1 / BiomeTextureScale)
+		fmt.Fprintf(&s.defs, `<filter id="tint-%s" color-interpolation-filters="sRGB"><feColorMatrix type="matrix" values="%s 0 0 0 0 0 %s 0 0 0 0 0 %s 0 0 0 0 0 1 0"/></filter>`+"\n",
+			sanitizeFileName(name), svgNum(float64(clr.R)/255), svgNum(float64(clr.G)/255), svgNum(float64(clr.B)/255))
+		fmt.Fprintf(&s.defs, `<pattern id="%s" patternUnits="userSpaceOnUse" width="%s" height="%s"><image width="%s" height="%s" preserveAspectRatio="none" filter="url(#tint-%s)" xlink:href="%s"/></pattern>`+"\n",
+			id, size, size, size, size, sanitizeFileName(name), href)
+		s.defined[id] = true
+	}
+	return "url(#" + id + ")"
+}
+
+// icon draws the named icon centered on x, y with its longest side size.
+func (s *svgWriter) icon(name string, x, y, size float64, title string) {
+	if name == "" {
+		return
+	}
+	img := renderAsset(name)
+	if img == nil {
+		return
+	}
+	ib := img.Bounds()
+	scale := size / math.Max(float64(ib.Dx()), float64(ib.Dy()))
+	w, h := float64(ib.Dx())*scale, float64(ib.Dy())*scale
+	id := "icon-" + sanitizeFileName(strings.TrimSuffix(name, filepath.Ext(name)))
+	if !s.defined[id] {
+		href, err := svgImageData(img)
+		if err != nil {
+			return
+		}
+		fmt.Fprintf(&s.defs, `<symbol id="%s" viewBox="0 0 %d %d"><image width="%d" height="%d" xlink:href="%s"/></symbol>`+"\n",
+			id, ib.Dx(), ib.Dy(), ib.Dx(), ib.Dy(), href)
+		s.defined[id] = true
+	}
+	fmt.Fprintf(&s.body, `<use xlink:href="#%s" x="%s" y="%s" width="%s" height="%s"><title>%s</title></use>`+"\n",
+		id, svgNum(x-w/2), svgNum(y-h/2), svgNum(w), svgNum(h), html.EscapeString(title))
+}
+
+// textWidth returns the width of text in world cells. Text is drawn at
+// baseFontSize pixels at the default scale of two pixels per cell.
+func (s *svgWriter) textWidth(text string) float64 {
+	return float64(font.MeasureString(s.face, text).Ceil()) / 2
+}
+
+// label draws text on a translucent background like drawTextWithBG, with an
+// optional border. When center is true x is the horizontal center of the
+// text.
+func (s *svgWriter) label(text string, x, y float64, center bool, border color.Color) {
+	lines := strings.Split(text, "\n")
+	m := s.face.Metrics()
+	lineH := float64(m.Height.Ceil()) / 2
+	ascent := float64(m.Ascent.Ceil()) / 2
+	w := 0.0
+	for _, l := range lines {
+		w = math.Max(w, s.textWidth(l))
+	}
+	left := x
+	anchor := ""
+	if center {
+		left = x - w/2
+		anchor = ` text-anchor="middle"`
+	}
+	const pad = 1
+	stroke := ""
+	if border != nil {
+		stroke = fmt.Sprintf(` stroke="%s" stroke-width="0.5"`, svgColor(border))
+	}
+	fmt.Fprintf(&s.body, `<rect x="%s" y="%s" width="%s" height="%s" fill="%s" fill-opacity="%s"%s/>`+"\n",
+		svgNum(left-pad), svgNum(y-pad), svgNum(w+2*pad), svgNum(lineH*float64(len(lines))+2*pad),
+		svgColor(legendBGColor), svgNum(float64(legendBGColor.A)/255), stroke)
+	for i, l := range lines {
+		fmt.Fprintf(&s.body, `<text x="%s" y="%s" font-size="%s" fill="#ffffff"%s>%s</text>`+"\n",
+			svgNum(x), svgNum(y+float64(i)*lineH+ascent), svgNum(baseFontSize/2), anchor, html.EscapeString(l))
+	}
+}
+
+// annotation draws a user annotation the way drawAnnotation does on screen.
+func (s *svgWriter) annotation(a annotation) {
+	clr := svgColor(annotationColor)
+	x, y := float64(a.X), float64(a.Y)
+	const r = 3.0
+	labelX, labelY := x, y+2
+	text := a.Text
+	switch a.Kind {
+	case annotationPin:
+		fmt.Fprintf(&s.body, `<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="%s" stroke-width="1"/>`+"\n",
+			svgNum(x), svgNum(y), svgNum(x), svgNum(y-3*r), clr)
+		fmt.Fprintf(&s.body, `<circle cx="%s" cy="%s" r="%s" fill="%s" stroke="#ffffff" stroke-width="0.5"/>`+"\n",
+			svgNum(x), svgNum(y-3*r), svgNum(r), clr)
+	case annotationNote:
+		fmt.Fprintf(&s.body, `<circle cx="%s" cy="%s" r="%s" fill="%s"/>`+"\n", svgNum(x), svgNum(y), svgNum(r/2), clr)
+	case annotationRect:
+		x2, y2 := float64(a.X2), float64(a.Y2)
+		left, top := math.Min(x, x2), math.Min(y, y2)
+		fmt.Fprintf(&s.body, `<rect x="%s" y="%s" width="%s" height="%s" fill="none" stroke="%s" stroke-width="1"/>`+"\n",
+			svgNum(left), svgNum(top), svgNum(math.Abs(x2-x)), svgNum(math.Abs(y2-y)), clr)
+		labelX, labelY = (x+x2)/2, math.Max(y, y2)+2
+	case annotationCircle:
+		rad := math.Hypot(float64(a.X2)-x, float64(a.Y2)-y)
+		fmt.Fprintf(&s.body, `<circle cx="%s" cy="%s" r="%s" fill="none" stroke="%s" stroke-width="1"/>`+"\n",
+			svgNum(x), svgNum(y), svgNum(rad), clr)
+		labelY = y + rad + 2
+	}
+	if text != "" {
+		s.label(text, labelX, labelY, true, annotationColor)
+	}
+}
+
+// legend draws the biomes and item types of ast in a column starting at x
+// and returns its width and height.
+func (s *svgWriter) legend(ast Asteroid, x float64) (float64, float64) {
+	biomes := map[string]bool{}
+	for _, bp := range ast.BiomePaths.Paths {
+		biomes[bp.Name] = true
+	}
+	names := make([]string, 0, len(biomes))
+	for n := range biomes {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	type entry struct {
+		icon  string
+		count int
+	}
+	items := map[string]*entry{}
+	add := func(name, icon string) {
+		if e, ok := items[name]; ok {
+			e.count++
+		} else {
+			items[name] = &entry{icon, 1}
+		}
+	}
+	for _, gy := range ast.Geysers {
+		add(displayGeyser(gy.ID), iconForGeyser(gy.ID))
+	}
+	for _, p := range ast.POIs {
+		add(displayPOI(p.ID), iconForPOI(p.ID))
+	}
+	itemNames := make([]string, 0, len(items))
+	for n := range items {
+		itemNames = append(itemNames, n)
+	}
+	sort.Strings(itemNames)
+
+	const pad, swatch = 4.0, 10.0
+	row := float64(s.face.Metrics().Height.Ceil())/2 + 2
+	textX := pad + swatch + 3
+	w := s.textWidth("Biomes")
+	for _, n := range names {
+		w = math.Max(w, textX-pad+s.textWidth(displayBiome(n)))
+	}
+	itemText := func(n string) string {
+		if c := items[n].count; c > 1 {
+			return fmt.Sprintf("%s x%d", n, c)
+		}
+		return n
+	}
+	for _, n := range itemNames {
+		w = math.Max(w, textX-pad+s.textWidth(itemText(n)))
+	}
+	w += 2 * pad
+	h := pad + row*float64(len(names)+1) + pad
+	if len(itemNames) > 0 {
+		h += row * float64(len(itemNames)+1)
+	}
+
+	fmt.Fprintf(&s.body, `<g id="legend" transform="translate(%s 0)">`+"\n", svgNum(x))
+	fmt.Fprintf(&s.body, `<rect width="%s" height="%s" fill="%s"/>`+"\n", svgNum(w), svgNum(h), svgColor(backgroundColor))
+	y := pad
+	heading := func(text string) {
+		fmt.Fprintf(&s.body, `<text x="%s" y="%s" font-size="%s" font-weight="bold" fill="#ffffff">%s</text>`+"\n",
+			svgNum(pad), svgNum(y+row-2), svgNum(baseFontSize/2), html.EscapeString(text))
+		y += row
+	}
+	entryText := func(text string) {
+		fmt.Fprintf(&s.body, `<text x="%s" y="%s" font-size="%s" fill="#ffffff">%s</text>`+"\n",
+			svgNum(textX), svgNum(y+row-2), svgNum(baseFontSize/2), html.EscapeString(text))
+	}
+	heading("Biomes")
+	for _, n := range names {
+		fmt.Fprintf(&s.body, `<rect x="%s" y="%s" width="%s" height="%s" fill="%s" stroke="#ffffff" stroke-width="0.25"/>`+"\n",
+			svgNum(pad), svgNum(y+1), svgNum(swatch), svgNum(row-2), s.biomeFill(n))
+		entryText(displayBiome(n))
+		y += row
+	}
+	if len(itemNames) > 0 {
+		heading("Geysers and POIs")
+		for _, n := range itemNames {
+			s.icon(items[n].icon, pad+swatch/2, y+row/2, row-1, n)
+			entryText(itemText(n))
+			y += row
+		}
+	}
+	s.body.WriteString("</g>\n")
+	return w, h
+}
+
+// svgPath returns the path data of polys, one closed subpath per polygon.
+func svgPath(polys [][]Point) string {
+	var b strings.Builder
+	for _, pts := range polys {
+		if len(pts) < 3 {
+			continue
+		}
+		for i, p := range pts {
+			if i == 0 {
+				b.WriteString("M")
+			} else {
+				b.WriteString(" L")
+			}
+			b.WriteString(strconv.Itoa(p.X) + " " + strconv.Itoa(p.Y))
+		}
+		b.WriteString(" Z")
+	}
+	return b.String()
+}
+
+// svgImageData returns img as a PNG data URI.
+func svgImageData(img image.Image) (string, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return "", err
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+func svgColor(c color.Color) string {
+	r, g, b, a := c.RGBA()
+	if a > 0 && a < 0xffff {
+		r, g, b = r*0xffff/a, g*0xffff/a, b*0xffff/a
+	}
+	return fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+}
+
+// svgNum formats v with at most three decimals and no trailing zeros.
+func svgNum(v float64) string {
+	return strconv.FormatFloat(math.Round(v*1000)/1000, 'f', -1, 64)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+)
+
+// TestWriteAsteroidSVG checks the exporter writes well-formed SVG with one
+// path per biome, the biome colors and the legend.
+func TestWriteAsteroidSVG(t *testing.T) {
+	ast := Asteroid{
+		ID:    "Terra",
+		SizeX: 20,
+		SizeY: 10,
+		BiomePaths: BiomePathsCompact{Paths: []BiomePath{{
+			Name:     "Sandstone",
+			Polygons: [][]Point{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}, {{2, 2}, {4, 2}, {4, 4}}},
+		}}},
+		Geysers: []Geyser{{ID: "steam", X: 5, Y: 5}},
+	}
+	opts := svgOptions{
+		renderOptions: renderOptions{Scale: 2, Icons: true, Labels: true, Legend: true, Title: "A & B"},
+		Annotations:   []annotation{{Kind: annotationNote, X: 1, Y: 1, Text: "<base>"}},
+	}
+	var buf bytes.Buffer
+	if err := writeAsteroidSVG(&buf, ast, opts); err != nil {
+		t.Fatal(err)
+	}
+	paths := 0
+	dec := xml.NewDecoder(bytes.NewReader(buf.Bytes()))
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("invalid XML: %v", err)
+		}
+		if el, ok := tok.(xml.StartElement); ok && el.Name.Local == "path" {
+			paths++
+		}
+	}
+	if paths != 1 {
+		t.Errorf("got %d paths, want 1", paths)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		`d="M0 0 L10 0 L10 10 L0 10 ZM2 2 L4 2 L4 4 Z"`,
+		`fill="` + svgColor(biomeColors["Sandstone"]) + `"`,
+		`id="legend"`,
+		"&lt;base&gt;",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %s", want)
+		}
+	}
+}
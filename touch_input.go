@@ -172,6 +172,8 @@ func (g *Game) handleTouchGestures(oldX, oldY float64) {
 				if g.geyserCloseRect().Overlaps(pt) {
 					g.showGeyserList = false
 					g.needsRedraw = true
+				} else if !g.geyserRect().Overlaps(pt) {
+					g.clickGeyserList(image.Pt(mx, my))
 				}
 			} else if g.showShotMenu {
 				if g.screenshotRect().Overlaps(pt) {
@@ -198,6 +200,7 @@ func (g *Game) handleTouchGestures(oldX, oldY float64) {
 				g.closeMenus()
 				g.showShotMenu = true
 				g.noColor = g.ssNoColor
+				g.ssErr = ""
 				g.needsRedraw = true
 			} else if g.optionsRect().Overlaps(pt) {
 				g.closeMenus()
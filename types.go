@@ -34,12 +34,17 @@ type BiomePathsCompact struct {
 }
 
 type Asteroid struct {
-	ID         string
-	SizeX      int
-	SizeY      int
+	ID    string
+	SizeX int
+	SizeY int
+	// OffsetX and OffsetY give the asteroid position within the cluster.
+	OffsetX    int
+	OffsetY    int
 	Geysers    []Geyser
 	POIs       []PointOfInterest
 	BiomePaths BiomePathsCompact
+	// Traits lists world trait IDs such as "GeoActive", see worldTraits.
+	Traits []string
 }
 
 type SeedData struct {
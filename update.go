@@ -12,8 +12,31 @@ import (
 func (g *Game) Update() error {
 	const panSpeed = PanSpeed
 
+	g.pollSeedLoad()
+	g.persistSettings()
 	g.checkRedrawTriggers()
 	g.processScreenshot()
+	g.updateFlight()
+	g.updatePageURL()
+
+	if g.handleSeedInput() {
+		return nil
+	}
+	if g.handleClusterMapInput() {
+		return nil
+	}
+	if g.handleScoreInput() {
+		return nil
+	}
+	if g.handleFindInput() {
+		return nil
+	}
+	if g.handleAnnotateInput() {
+		return nil
+	}
+	if g.handleCompareInput() {
+		return nil
+	}
 
 	oldX, oldY, oldZoom := g.camX, g.camY, g.zoom
 
@@ -26,19 +49,24 @@ func (g *Game) Update() error {
 		return nil
 	}
 
-	// Keyboard panning
-	if ebiten.IsKeyPressed(ebiten.KeyLeft) || ebiten.IsKeyPressed(ebiten.KeyA) {
+	// Keyboard panning, unless the keys are typed into the search box
+	keys := !g.textInputActive()
+	if keys && (ebiten.IsKeyPressed(ebiten.KeyLeft) || ebiten.IsKeyPressed(ebiten.KeyA)) {
 		g.camX += panSpeed
 	}
-	if ebiten.IsKeyPressed(ebiten.KeyRight) || ebiten.IsKeyPressed(ebiten.KeyD) {
+	if keys && (ebiten.IsKeyPressed(ebiten.KeyRight) || ebiten.IsKeyPressed(ebiten.KeyD)) {
 		g.camX -= panSpeed
 	}
-	if ebiten.IsKeyPressed(ebiten.KeyUp) || ebiten.IsKeyPressed(ebiten.KeyW) {
+	if keys && (ebiten.IsKeyPressed(ebiten.KeyUp) || ebiten.IsKeyPressed(ebiten.KeyW)) {
 		g.camY += panSpeed
 	}
-	if ebiten.IsKeyPressed(ebiten.KeyDown) || ebiten.IsKeyPressed(ebiten.KeyS) {
+	if keys && (ebiten.IsKeyPressed(ebiten.KeyDown) || ebiten.IsKeyPressed(ebiten.KeyS)) {
 		g.camY -= panSpeed
 	}
+	if keys && inpututil.IsKeyJustPressed(ebiten.KeyG) {
+		g.showGrid = !g.showGrid
+		g.needsRedraw = true
+	}
 
 	mxTmp, myTmp := ebiten.CursorPosition()
 	mousePressed := ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft)
@@ -67,10 +95,10 @@ func (g *Game) Update() error {
 
 	// Zoom with keyboard
 	zoomFactor := 1.0
-	if ebiten.IsKeyPressed(ebiten.KeyEqual) || ebiten.IsKeyPressed(ebiten.KeyKPAdd) {
+	if keys && (ebiten.IsKeyPressed(ebiten.KeyEqual) || ebiten.IsKeyPressed(ebiten.KeyKPAdd)) {
 		zoomFactor *= KeyZoomFactor
 	}
-	if ebiten.IsKeyPressed(ebiten.KeyMinus) || ebiten.IsKeyPressed(ebiten.KeyKPSubtract) {
+	if keys && (ebiten.IsKeyPressed(ebiten.KeyMinus) || ebiten.IsKeyPressed(ebiten.KeyKPSubtract)) {
 		zoomFactor /= KeyZoomFactor
 	}
 
@@ -158,6 +186,7 @@ func (g *Game) Update() error {
 			g.touchUsed = false
 		}
 		g.lastMouseX, g.lastMouseY = mx, my
+		g.updateCursorTile(mx, my)
 		if justPressed && g.helpRect().Overlaps(image.Rect(mx, my, mx+1, my+1)) {
 			g.showHelp = !g.showHelp
 			g.lastHelpClick = time.Now()
@@ -201,6 +230,7 @@ func (g *Game) Update() error {
 				g.closeMenus()
 				g.showShotMenu = true
 				g.noColor = g.ssNoColor
+				g.ssErr = ""
 			}
 			g.lastShotClick = time.Now()
 			g.needsRedraw = true
@@ -320,9 +350,5 @@ func (g *Game) Update() error {
 		g.needsRedraw = true
 	}
 
-	if g.captured {
-		return ebiten.Termination
-	}
-
 	return nil
 }
@@ -3,10 +3,12 @@ package main
 import (
 	"image"
 	"image/color"
+	"math"
 	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
 )
 
 func (g *Game) checkRedrawTriggers() {
@@ -14,6 +16,9 @@ func (g *Game) checkRedrawTriggers() {
 		g.centerAndFit()
 		g.needsRedraw = true
 		g.fitOnLoad = false
+		if g.pendingView != nil {
+			g.applyPendingView()
+		}
 	}
 	if !g.smartRender {
 		g.needsRedraw = true
@@ -31,10 +36,15 @@ func (g *Game) checkRedrawTriggers() {
 func (g *Game) processScreenshot() {
 	if g.ssPending > 0 {
 		if g.ssPending == 1 {
-			g.saveScreenshot()
 			g.ssSaved = time.Now()
-			g.showShotMenu = false
-			g.noColor = false
+			g.ssErr = ""
+			if err := g.saveScreenshot(); err != nil {
+				// Keep the menu open to show the error.
+				g.ssErr = "Screenshot not saved: " + err.Error()
+			} else {
+				g.showShotMenu = false
+				g.noColor = false
+			}
 			g.skipClickTicks = 1
 		}
 		g.ssPending--
@@ -52,6 +62,17 @@ func (g *Game) handleGeyserListInput() bool {
 	if wheelY != 0 {
 		g.adjustGeyserScroll(-float64(wheelY) * 10)
 	}
+	g.typeGeyserQuery()
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		if g.geyserQuery != "" {
+			g.geyserQuery = ""
+			g.refreshGeyserList()
+		} else {
+			g.showGeyserList = false
+			g.needsRedraw = true
+		}
+		return true
+	}
 	mx, my := ebiten.CursorPosition()
 	if mx >= 0 && mx < g.width && my >= 0 && my < g.height {
 		if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
@@ -62,6 +83,8 @@ func (g *Game) handleGeyserListInput() bool {
 			} else if g.geyserCloseRect().Overlaps(image.Rect(mx, my, mx+1, my+1)) {
 				g.showGeyserList = false
 				g.needsRedraw = true
+			} else {
+				g.clickGeyserList(image.Pt(mx, my))
 			}
 		}
 	}
@@ -144,6 +167,15 @@ func (g *Game) drawLoadingScreen(dst *ebiten.Image) bool {
 		msg = "Fetching asteroid data..."
 		scale = 2.0
 	}
+	if g.load != nil {
+		head := g.load.coord
+		if d := describeCoordinate(head); d != "" {
+			head += "\n" + d
+		}
+		msg = head + "\n" + msg + "\n\nPress Esc to cancel"
+	} else if !g.loading {
+		msg += "\n\nPress L or tap to enter another seed"
+	}
 	_, h := textDimensions(msg)
 	x := g.width / 2
 	y := g.height/2 - int(float64(h)*scale/2)
@@ -152,6 +184,18 @@ func (g *Game) drawLoadingScreen(dst *ebiten.Image) bool {
 	} else {
 		drawTextWithBGScale(dst, msg, x, y, scale, true)
 	}
+	if g.load != nil && g.loadFraction >= 0 {
+		barW := uiScaled(LoadingBarWidth)
+		barH := uiScaled(6)
+		bx := x - barW/2
+		by := y + int(float64(h)*scale) + uiScaled(12)
+		frac := math.Min(g.loadFraction, 1)
+		vector.DrawFilledRect(dst, float32(bx), float32(by), float32(barW), float32(barH), scrollBarTrackColor, false)
+		vector.DrawFilledRect(dst, float32(bx), float32(by), float32(float64(barW)*frac), float32(barH), scrollBarColor, false)
+	}
+	if g.showSeedInput {
+		g.drawSeedInput(dst)
+	}
 	g.lastDraw = time.Now()
 	return true
 }
@@ -2,12 +2,44 @@
 
 package main
 
-// coordFromURL returns an empty string on non-web builds.
-func coordFromURL() string {
+import (
+	"errors"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// pageURL returns an empty string on non-web builds.
+func pageURL() string {
 	return ""
 }
 
-// asteroidFromURL returns "" on non-web builds.
-func asteroidFromURL() (string, bool) {
-	return "", false
+// replacePageURL is a no-op on non-web builds.
+func replacePageURL(_ string) {}
+
+// shareBaseURL returns the address given with -share-base.
+func shareBaseURL() string {
+	return shareBase
+}
+
+// copyToClipboard copies text using the system clipboard tool.
+func copyToClipboard(text string) error {
+	var cmds [][]string
+	switch runtime.GOOS {
+	case "windows":
+		cmds = [][]string{{"clip"}}
+	case "darwin":
+		cmds = [][]string{{"pbcopy"}}
+	default:
+		cmds = [][]string{{"wl-copy"}, {"xclip", "-selection", "clipboard"}, {"xsel", "--clipboard", "--input"}}
+	}
+	for _, c := range cmds {
+		if _, err := exec.LookPath(c[0]); err != nil {
+			continue
+		}
+		cmd := exec.Command(c[0], c[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		return cmd.Run()
+	}
+	return errors.New("no clipboard tool found")
 }
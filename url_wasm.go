@@ -3,61 +3,46 @@
 package main
 
 import (
-	"net/url"
-	"strings"
+	"errors"
 	"syscall/js"
 )
 
-// coordFromURL retrieves the seed coordinate from the URL if present.
-// It supports query parameters like ?coord=SEED or ?seed=SEED and
-// also checks the hash fragment for the same patterns.
-func coordFromURL() string {
+// pageURL returns the address of the viewer page.
+func pageURL() string {
 	loc := js.Global().Get("location")
 	if !loc.Truthy() {
 		return ""
 	}
-	search := loc.Get("search").String()
-	search = strings.TrimPrefix(search, "?")
-	for _, part := range strings.Split(search, "&") {
-		if strings.HasPrefix(part, "coord=") {
-			return strings.TrimPrefix(part, "coord=")
-		}
-		if strings.HasPrefix(part, "seed=") {
-			return strings.TrimPrefix(part, "seed=")
-		}
-	}
-	hash := loc.Get("hash").String()
-	hash = strings.TrimPrefix(hash, "#")
-	if strings.HasPrefix(hash, "coord=") {
-		return strings.TrimPrefix(hash, "coord=")
-	}
-	if strings.HasPrefix(hash, "seed=") {
-		return strings.TrimPrefix(hash, "seed=")
-	}
-	if hash != "" && !strings.Contains(hash, "=") {
-		return hash
-	}
-	return ""
+	return loc.Get("href").String()
+}
+
+// replacePageURL replaces the query of the page address without adding a
+// history entry, so the address bar always links to the current view.
+func replacePageURL(query string) {
+	hist := js.Global().Get("history")
+	if !hist.Truthy() {
+		return
+	}
+	hist.Call("replaceState", js.Null(), "", "?"+query)
 }
 
-// asteroidFromURL retrieves the asteroid ID from the URL if present.
-// It looks for an `asteroid=ID` query parameter or hash fragment.
-func asteroidFromURL() (string, bool) {
+// shareBaseURL returns the page address without query or fragment.
+func shareBaseURL() string {
 	loc := js.Global().Get("location")
 	if !loc.Truthy() {
-		return "", false
-	}
-	search := strings.TrimPrefix(loc.Get("search").String(), "?")
-	for _, part := range strings.Split(search, "&") {
-		if strings.HasPrefix(part, "asteroid=") {
-			id, _ := url.QueryUnescape(strings.TrimPrefix(part, "asteroid="))
-			return normalizeAsteroidID(id), true
-		}
-	}
-	hash := strings.TrimPrefix(loc.Get("hash").String(), "#")
-	if strings.HasPrefix(hash, "asteroid=") {
-		id, _ := url.QueryUnescape(strings.TrimPrefix(hash, "asteroid="))
-		return normalizeAsteroidID(id), true
+		return DefaultShareBase
 	}
-	return "", false
+	return loc.Get("origin").String() + loc.Get("pathname").String()
+}
+
+// copyToClipboard copies text with the asynchronous clipboard API. Browsers
+// only allow it in response to user input, so failures are reported by the
+// returned promise and not here.
+func copyToClipboard(text string) error {
+	clip := js.Global().Get("navigator").Get("clipboard")
+	if !clip.Truthy() {
+		return errors.New("clipboard unavailable")
+	}
+	clip.Call("writeText", text)
+	return nil
 }
@@ -0,0 +1,36 @@
+//go:build !js
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// userDataDir returns the per-user directory holding saved annotations and
+// settings, or "" when no config directory is available.
+func userDataDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, ConfigDirName)
+}
+
+// readUserData returns the contents of the named file in userDataDir.
+func readUserData(name string) ([]byte, error) {
+	dir := userDataDir()
+	if dir == "" {
+		return nil, os.ErrNotExist
+	}
+	return os.ReadFile(filepath.Join(dir, name))
+}
+
+// writeUserData replaces the named file in userDataDir with data.
+func writeUserData(name string, data []byte) error {
+	dir := userDataDir()
+	if dir == "" {
+		return os.ErrNotExist
+	}
+	return writeFileAtomic(filepath.Join(dir, name), data)
+}
@@ -0,0 +1,44 @@
+//go:build js && wasm
+
+package main
+
+import (
+	"errors"
+	"os"
+	"syscall/js"
+)
+
+// localStorage returns the browser storage, which is undefined when storage
+// is disabled.
+func localStorage() js.Value {
+	return js.Global().Get("localStorage")
+}
+
+// readUserData returns the named entry from the browser's localStorage.
+func readUserData(name string) ([]byte, error) {
+	ls := localStorage()
+	if !ls.Truthy() {
+		return nil, os.ErrNotExist
+	}
+	v := ls.Call("getItem", ConfigDirName+"/"+name)
+	if v.IsNull() || v.IsUndefined() {
+		return nil, os.ErrNotExist
+	}
+	return []byte(v.String()), nil
+}
+
+// writeUserData stores data under name in the browser's localStorage.
+func writeUserData(name string, data []byte) (err error) {
+	ls := localStorage()
+	if !ls.Truthy() {
+		return errors.New("localStorage is not available")
+	}
+	// setItem throws when the storage quota is exceeded.
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.New("localStorage is full")
+		}
+	}()
+	ls.Call("setItem", ConfigDirName+"/"+name, string(data))
+	return nil
+}
@@ -0,0 +1,327 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// linkLayerFields ties each overlay and option name of the "layers" link
+// parameter to the Game field it toggles and the settings field it is saved
+// in.
+var linkLayerFields = []struct {
+	name     string
+	game     func(*Game) *bool
+	settings func(*settings) *bool
+}{
+	{"names", func(g *Game) *bool { return &g.showItemNames }, func(s *settings) *bool { return &s.ShowItemNames }},
+	{"legend", func(g *Game) *bool { return &g.showLegend }, func(s *settings) *bool { return &s.ShowLegend }},
+	{"numbers", func(g *Game) *bool { return &g.useNumbers }, func(s *settings) *bool { return &s.UseNumbers }},
+	{"pods", func(g *Game) *bool { return &g.showPodLines }, func(s *settings) *bool { return &s.ShowPodLines }},
+	{"notes", func(g *Game) *bool { return &g.showAnnotations }, func(s *settings) *bool { return &s.ShowAnnotations }},
+	{"grid", func(g *Game) *bool { return &g.showGrid }, func(s *settings) *bool { return &s.ShowGrid }},
+	{"textures", func(g *Game) *bool { return &g.textures }, func(s *settings) *bool { return &s.Textures }},
+	{"flipy", func(g *Game) *bool { return &g.flipY }, func(s *settings) *bool { return &s.FlipY }},
+}
+
+// linkLayers are the names of linkLayerFields in link order.
+var linkLayers = func() []string {
+	names := make([]string, len(linkLayerFields))
+	for i, f := range linkLayerFields {
+		names[i] = f.name
+	}
+	return names
+}()
+
+// shareBase is the viewer address desktop share links point at, set with
+// the -share-base flag. Web builds use the page address.
+var shareBase = DefaultShareBase
+
+// viewState is what a deep link describes: the seed and asteroid, the camera
+// center in world tiles and zoom, the pinned item and the enabled layers.
+// Parts missing from a link keep their current values.
+type viewState struct {
+	Coord    string
+	Asteroid string
+	// HasCamera is set when X, Y and Zoom were given.
+	HasCamera bool
+	X, Y      float64
+	Zoom      float64
+	// HasItem is set when the details of the item at Item are pinned.
+	HasItem bool
+	Item    Point
+	// Layers lists the enabled linkLayers; nil leaves them unchanged.
+	Layers []string
+}
+
+// encode returns the query string of v, such as
+// "asteroid=...&coord=...&item=10,20&layers=grid,names&x=...&y=...&zoom=...".
+func (v viewState) encode() string {
+	q := url.Values{}
+	q.Set("coord", v.Coord)
+	if v.Asteroid != "" {
+		q.Set("asteroid", v.Asteroid)
+	}
+	if v.HasCamera {
+		q.Set("x", strconv.FormatFloat(v.X, 'f', 1, 64))
+		q.Set("y", strconv.FormatFloat(v.Y, 'f', 1, 64))
+		q.Set("zoom", strconv.FormatFloat(v.Zoom, 'f', 3, 64))
+	}
+	if v.HasItem {
+		q.Set("item", fmt.Sprintf("%d,%d", v.Item.X, v.Item.Y))
+	}
+	if v.Layers != nil {
+		q.Set("layers", strings.Join(v.Layers, ","))
+	}
+	// Keep the commas readable.
+	return strings.ReplaceAll(q.Encode(), "%2C", ",")
+}
+
+// parseViewLink reads a deep link. raw may be a full viewer URL, a bare
+// query string or an older link with only "coord", "seed" or "asteroid",
+// in the query or the fragment, or the coordinate alone as the fragment.
+func parseViewLink(raw string) (viewState, error) {
+	var v viewState
+	raw = strings.TrimSpace(raw)
+	if !strings.Contains(raw, "?") && !strings.Contains(raw, "#") && strings.Contains(raw, "=") {
+		raw = "?" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return v, err
+	}
+	q := u.Query()
+	if frag := u.Fragment; frag != "" {
+		if !strings.Contains(frag, "=") {
+			if q.Get("coord") == "" {
+				q.Set("coord", frag)
+			}
+		} else if fq, err := url.ParseQuery(frag); err == nil {
+			for k, vals := range fq {
+				if _, ok := q[k]; !ok {
+					q[k] = vals
+				}
+			}
+		}
+	}
+	v.Coord = q.Get("coord")
+	if v.Coord == "" {
+		v.Coord = q.Get("seed")
+	}
+	v.Coord = strings.ToUpper(strings.TrimSpace(v.Coord))
+	if a := q.Get("asteroid"); a != "" {
+		v.Asteroid = normalizeAsteroidID(a)
+	}
+	if q.Has("x") || q.Has("y") || q.Has("zoom") {
+		x, errX := strconv.ParseFloat(q.Get("x"), 64)
+		y, errY := strconv.ParseFloat(q.Get("y"), 64)
+		zoom, errZ := strconv.ParseFloat(q.Get("zoom"), 64)
+		if errX != nil || errY != nil || errZ != nil || zoom <= 0 || math.IsInf(zoom, 0) {
+			return v, fmt.Errorf("invalid camera position x=%q y=%q zoom=%q", q.Get("x"), q.Get("y"), q.Get("zoom"))
+		}
+		v.HasCamera, v.X, v.Y, v.Zoom = true, x, y, zoom
+	}
+	if item := q.Get("item"); item != "" {
+		var p Point
+		if _, err := fmt.Sscanf(item, "%d,%d", &p.X, &p.Y); err != nil {
+			return v, fmt.Errorf("invalid item %q", item)
+		}
+		v.HasItem, v.Item = true, p
+	}
+	if q.Has("layers") {
+		v.Layers = []string{}
+		for _, l := range strings.Split(q.Get("layers"), ",") {
+			l = strings.ToLower(strings.TrimSpace(l))
+			if l == "" {
+				continue
+			}
+			if !containsString(linkLayers, l) {
+				return v, fmt.Errorf("unknown layer %q (valid: %s)", l, strings.Join(linkLayers, ", "))
+			}
+			v.Layers = append(v.Layers, l)
+		}
+	}
+	return v, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// layerFlags maps the linkLayers to the fields they toggle.
+func (g *Game) layerFlags() map[string]*bool {
+	flags := make(map[string]*bool, len(linkLayerFields))
+	for _, f := range linkLayerFields {
+		flags[f.name] = f.game(g)
+	}
+	return flags
+}
+
+// layerFlags maps the linkLayers to their settings fields.
+func (s *settings) layerFlags() map[string]*bool {
+	flags := make(map[string]*bool, len(linkLayerFields))
+	for _, f := range linkLayerFields {
+		flags[f.name] = f.settings(s)
+	}
+	return flags
+}
+
+// pinnedItem returns the tile of the item whose details are pinned.
+func (g *Game) pinnedItem() (Point, bool) {
+	if !g.showInfo || !g.infoPinned {
+		return Point{}, false
+	}
+	for _, gy := range g.geysers {
+		if g.geyserInfo(gy) == g.infoText {
+			return Point{gy.X, gy.Y}, true
+		}
+	}
+	for _, poi := range g.pois {
+		if displayPOI(poi.ID)+"\n"+formatPOIInfo(poi) == g.infoText {
+			return Point{poi.X, poi.Y}, true
+		}
+	}
+	return Point{}, false
+}
+
+// pinItem shows and pins the details of the geyser or POI at p.
+func (g *Game) pinItem(p Point) bool {
+	sx := int(math.Round(float64(p.X)*2*g.zoom + g.camX))
+	sy := int(math.Round(float64(p.Y)*2*g.zoom + g.camY))
+	info, _, _, icon, found := g.itemAt(sx, sy)
+	if found {
+		g.infoText = info
+		g.infoIcon = icon
+		g.showInfo = true
+		g.infoPinned = true
+		g.needsRedraw = true
+	}
+	return found
+}
+
+// viewState returns the deep link state of the current view.
+func (g *Game) viewState() viewState {
+	v := viewState{Coord: g.coord, Asteroid: g.asteroidID, HasCamera: true, Zoom: g.zoom}
+	v.X, v.Y = g.viewCenter()
+	v.Item, v.HasItem = g.pinnedItem()
+	v.Layers = []string{}
+	flags := g.layerFlags()
+	for _, l := range linkLayers {
+		if *flags[l] {
+			v.Layers = append(v.Layers, l)
+		}
+	}
+	return v
+}
+
+// applyLinkLayers switches the layers named by v for this session only. The
+// layers it changes are remembered in g.linkOverrides so they are not saved as
+// the user's settings.
+func (g *Game) applyLinkLayers(v viewState) {
+	if v.Layers == nil {
+		return
+	}
+	g.linkOverrides = map[string]bool{}
+	for l, f := range g.layerFlags() {
+		on := containsString(v.Layers, l)
+		if *f != on {
+			*f = on
+			g.linkOverrides[l] = on
+		}
+	}
+	g.needsRedraw = true
+}
+
+// withoutLinkLayers replaces the layers of s still switched by the startup
+// link with their saved values. A layer the user toggles afterwards is no
+// longer a link override and is saved as usual.
+func (g *Game) withoutLinkLayers(s *settings) {
+	flags := s.layerFlags()
+	saved := g.savedSettings.layerFlags()
+	for l, on := range g.linkOverrides {
+		if *flags[l] != on {
+			delete(g.linkOverrides, l)
+			continue
+		}
+		*flags[l] = *saved[l]
+	}
+}
+
+// applyPendingView moves the camera and pins the item of the deep link
+// opened at startup once its asteroid is shown.
+func (g *Game) applyPendingView() {
+	v := g.pendingView
+	g.pendingView = nil
+	if v.HasCamera {
+		g.zoom = math.Min(math.Max(v.Zoom, g.minZoom), MaxZoom)
+		g.camX = float64(g.width)/2 - v.X*2*g.zoom
+		g.camY = float64(g.height)/2 - v.Y*2*g.zoom
+		g.clampCamera()
+	}
+	if v.HasItem {
+		g.pinItem(v.Item)
+	}
+	g.needsRedraw = true
+}
+
+// shareLink returns the deep link to the current view.
+func (g *Game) shareLink() string {
+	return shareBaseURL() + "?" + g.viewState().encode()
+}
+
+// shareView copies the deep link to the clipboard, or prints it to the
+// console when the clipboard is unavailable.
+func (g *Game) shareView() {
+	link := g.shareLink()
+	g.shareStatus = ShareCopiedLabel
+	if err := copyToClipboard(link); err != nil {
+		fmt.Println(link)
+		g.shareStatus = "Link printed to the console: " + err.Error()
+	}
+	g.shareTime = time.Now()
+	g.needsRedraw = true
+}
+
+// drawShareStatus shows the result of the last Share Link below the
+// asteroid name for ShareStatusDuration.
+func (g *Game) drawShareStatus(dst *ebiten.Image) {
+	if g.shareStatus == "" {
+		return
+	}
+	if time.Since(g.shareTime) >= ShareStatusDuration {
+		g.shareStatus = ""
+		return
+	}
+	r := g.asteroidInfoRect()
+	drawTextWithBG(dst, g.shareStatus, r.Min.X+r.Dx()/2, r.Max.Y+uiScaled(6), true)
+}
+
+// updatePageURL keeps the browser address bar in sync with the view, at
+// most every LinkUpdateInterval, and redraws once the share status expires.
+func (g *Game) updatePageURL() {
+	if g.shareStatus != "" && time.Since(g.shareTime) >= ShareStatusDuration {
+		g.needsRedraw = true
+	}
+	if g.loading || len(g.asteroids) == 0 || g.pendingView != nil || g.flight != nil {
+		return
+	}
+	if time.Since(g.linkUpdated) < LinkUpdateInterval {
+		return
+	}
+	link := g.viewState().encode()
+	if link != g.pageLink {
+		g.pageLink = link
+		g.linkUpdated = time.Now()
+		replacePageURL(link)
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestViewLinkRoundTrip(t *testing.T) {
+	v := viewState{
+		Coord:     "V-FRST-C-1331877-0-0-0",
+		Asteroid:  "Vanilla_Verdante",
+		HasCamera: true, X: 120.5, Y: 80, Zoom: 2.5,
+		HasItem: true, Item: Point{X: 118, Y: 77},
+		Layers: []string{"names", "grid"},
+	}
+	link := v.encode()
+	got, err := parseViewLink("https://example.org/view.html?" + link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, v) {
+		t.Errorf("parse(%q) = %+v, want %+v", link, got, v)
+	}
+}
+
+func TestParseViewLinkLegacy(t *testing.T) {
+	tests := []struct {
+		raw, coord string
+	}{
+		{"view.html?coord=v-frst-c-1", "V-FRST-C-1"},
+		{"view.html?seed=V-FRST-C-2", "V-FRST-C-2"},
+		{"view.html#V-FRST-C-3", "V-FRST-C-3"},
+		{"view.html#coord=V-FRST-C-4", "V-FRST-C-4"},
+		{"coord=V-FRST-C-5&zoom=2&x=1&y=1", "V-FRST-C-5"},
+	}
+	for _, tt := range tests {
+		v, err := parseViewLink(tt.raw)
+		if err != nil || v.Coord != tt.coord {
+			t.Errorf("parse(%q) = %q, %v, want %q", tt.raw, v.Coord, err, tt.coord)
+		}
+		if v.Layers != nil || v.HasItem {
+			t.Errorf("parse(%q) = %+v, want no layers or item", tt.raw, v)
+		}
+	}
+	for _, raw := range []string{"?coord=A&x=1&zoom=2", "?coord=A&item=3", "?coord=A&layers=names,bogus"} {
+		if _, err := parseViewLink(raw); err == nil {
+			t.Errorf("parse(%q) succeeded, want an error", raw)
+		}
+	}
+}
+
+func TestLinkLayersNotSaved(t *testing.T) {
+	g := &Game{showItemNames: true, showLegend: true, iconScale: 1}
+	g.savedSettings = g.currentSettings()
+	g.applyLinkLayers(viewState{Layers: []string{"grid", "legend"}})
+	if !g.showGrid || g.showItemNames || !g.showLegend {
+		t.Fatalf("layers not applied: grid %v names %v legend %v", g.showGrid, g.showItemNames, g.showLegend)
+	}
+	s := g.currentSettings()
+	g.withoutLinkLayers(&s)
+	if s != g.savedSettings {
+		t.Errorf("link layers would be saved: %+v", s)
+	}
+
+	// The user turns names back on and changes another option.
+	g.showItemNames = true
+	g.flipY = true
+	s = g.currentSettings()
+	g.withoutLinkLayers(&s)
+	if !s.ShowItemNames || !s.FlipY || s.ShowGrid {
+		t.Errorf("got %+v, want names and flipy saved without the link grid", s)
+	}
+}
+
+// TestLinkLayerFields verifies each layer's Game and settings fields belong
+// together, so withoutLinkLayers restores the option that was switched.
+func TestLinkLayerFields(t *testing.T) {
+	for _, l := range linkLayers {
+		g := &Game{}
+		*g.layerFlags()[l] = true
+		s := g.currentSettings()
+		for name, f := range s.layerFlags() {
+			if *f != (name == l) {
+				t.Errorf("layer %s: settings %s = %v", l, name, *f)
+			}
+		}
+	}
+}
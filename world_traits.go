@@ -0,0 +1,69 @@
+package main
+
+import "strings"
+
+// worldTrait is a world generation trait that can be present on an asteroid.
+type worldTrait struct {
+	ID   string
+	Name string
+}
+
+// worldTraits lists traits in bit order of Asteroid.worldTraitsBitmask in
+// seed.proto: bit 0 is BouldersLarge, bit 1 BouldersMedium and so on.
+var worldTraits = []worldTrait{
+	{"BouldersLarge", "Large Boulders"},
+	{"BouldersMedium", "Medium Boulders"},
+	{"BouldersMixed", "Mixed Boulders"},
+	{"BouldersSmall", "Small Boulders"},
+	{"DeepOil", "Trapped Oil"},
+	{"FrozenCore", "Frozen Core"},
+	{"GeoActive", "Geoactive"},
+	{"Geodes", "Geodes"},
+	{"GeoDormant", "Geodormant"},
+	{"GlaciersLarge", "Large Glaciers"},
+	{"IrregularOil", "Irregular Oil"},
+	{"MagmaVents", "Magma Channels"},
+	{"MetalPoor", "Metal Poor"},
+	{"MetalRich", "Metal Rich"},
+	{"MisalignedStart", "Alternate Pod Location"},
+	{"SlimeSplats", "Slime Molds"},
+	{"SubsurfaceOcean", "Subsurface Ocean"},
+	{"Volcanoes", "Volcanic Activity"},
+	{"CrashedSatellites", "Crashed Satellites"},
+	{"DistressSignal", "Frozen Friend"},
+	{"LushCore", "Lush Core"},
+	{"MetalCaves", "Metallic Caves"},
+	{"RadioactiveCrust", "Radioactive Crust"},
+	{"SurfaceCracks", "Surface Fractures"},
+}
+
+// decodeWorldTraits returns the trait IDs set in mask. Unknown bits are
+// ignored.
+func decodeWorldTraits(mask int32) []string {
+	var ids []string
+	for i, t := range worldTraits {
+		if mask&(1<<uint(i)) != 0 {
+			ids = append(ids, t.ID)
+		}
+	}
+	return ids
+}
+
+// worldTraitName returns the display name for a trait ID.
+func worldTraitName(id string) string {
+	for _, t := range worldTraits {
+		if t.ID == id {
+			return t.Name
+		}
+	}
+	return id
+}
+
+// worldTraitNames joins the display names of ids with commas.
+func worldTraitNames(ids []string) string {
+	out := make([]string, len(ids))
+	for i, id := range ids {
+		out[i] = worldTraitName(id)
+	}
+	return strings.Join(out, ", ")
+}